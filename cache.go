@@ -0,0 +1,135 @@
+package zoom
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/pkg/errors"
+	calendar "google.golang.org/api/calendar/v3"
+)
+
+// ErrNoCache indicates that no events have been cached yet.
+var ErrNoCache = errors.New("no cached events")
+
+// EventCache stores recently fetched events on disk, so they're available when the Calendar API isn't.
+type EventCache struct {
+	path string
+}
+
+// CachedEvents is a snapshot of events as they were when last fetched.
+type CachedEvents struct {
+	FetchedAt time.Time         `json:"fetched_at"`
+	Events    []*calendar.Event `json:"events"`
+}
+
+// NewEventCache returns an EventCache stored in the file at path.
+func NewEventCache(path string) *EventCache {
+	return &EventCache{path: path}
+}
+
+// NewDefaultEventCache returns an EventCache stored in the user's cache directory,
+// e.g. $XDG_CACHE_HOME/zoom/events.json.
+func NewDefaultEventCache() (*EventCache, error) {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	return NewEventCache(filepath.Join(dir, "zoom", "events.json")), nil
+}
+
+// Load reads the cached events. If nothing has been cached, it returns ErrNoCache.
+func (c *EventCache) Load() (*CachedEvents, error) {
+	b, err := ioutil.ReadFile(c.path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, ErrNoCache
+		}
+		return nil, errors.WithStack(err)
+	}
+
+	cached := &CachedEvents{}
+	if err := json.Unmarshal(b, cached); err != nil {
+		return nil, errors.Wrapf(err, "unable to parse cache %s", c.path)
+	}
+	return cached, nil
+}
+
+// Update stores freshly fetched events, which are authoritative for the meetings starting between from and to.
+// Cached meetings outside that range are kept, unless they started more than a day ago.
+func (c *EventCache) Update(events []*calendar.Event, from, to time.Time) error {
+	now := time.Now()
+	merged := append([]*calendar.Event{}, events...)
+	fetched := map[string]bool{}
+	for _, event := range events {
+		fetched[event.Id] = true
+	}
+
+	if cached, err := c.Load(); err == nil {
+		for _, event := range cached.Events {
+			startTime, err := MeetingStartTime(event)
+			if err != nil || startTime.Before(now.Add(-24*time.Hour)) {
+				continue
+			}
+			// Meetings in progress may have started before the range, but they were fetched again.
+			if event.Id != "" && fetched[event.Id] {
+				continue
+			}
+			if !startTime.Before(from) && !startTime.After(to) {
+				continue
+			}
+			merged = append(merged, event)
+		}
+	}
+
+	sortEventsByStart(merged)
+
+	return writeJSONAtomic(c.path, &CachedEvents{FetchedAt: now, Events: merged})
+}
+
+// writeJSONAtomic encodes the value as JSON into the file at path, replacing it atomically
+// so readers never see a partially written file.
+func writeJSONAtomic(path string, v interface{}) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return errors.WithStack(err)
+	}
+
+	b, err := json.Marshal(v)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	tmp, err := ioutil.TempFile(filepath.Dir(path), "."+filepath.Base(path)+"-*")
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(b); err != nil {
+		tmp.Close()
+		return errors.WithStack(err)
+	}
+	if err := tmp.Close(); err != nil {
+		return errors.WithStack(err)
+	}
+	return errors.WithStack(os.Rename(tmp.Name(), path))
+}
+
+// Age returns how long ago the events were fetched.
+func (c *CachedEvents) Age() time.Duration {
+	return time.Since(c.FetchedAt)
+}
+
+// NextEvents returns the next N cached events allowed by the filter which started at most 5 minutes ago
+// or are still in progress, like NextEvents.
+func (c *CachedEvents) NextEvents(count int, filter Filter) []*calendar.Event {
+	return selectEvents(c.Events, time.Now().Add(-5*time.Minute), time.Time{}, count, filter)
+}
+
+// EventsBetween returns the cached events allowed by the filter which start before to and end after from,
+// like EventsBetween.
+func (c *CachedEvents) EventsBetween(from, to time.Time, filter Filter) []*calendar.Event {
+	return selectEvents(c.Events, from, to, 0, filter)
+}
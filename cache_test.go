@@ -0,0 +1,100 @@
+package zoom
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	calendar "google.golang.org/api/calendar/v3"
+)
+
+func cachedIDs(events []*calendar.Event) []string {
+	ids := []string{}
+	for _, event := range events {
+		ids = append(ids, event.Id)
+	}
+	return ids
+}
+
+func TestEventCache_NoCache(t *testing.T) {
+	cache := NewEventCache(filepath.Join(t.TempDir(), "zoom", "events.json"))
+	_, err := cache.Load()
+	assert.Equal(t, ErrNoCache, err)
+}
+
+func TestEventCache_Update(t *testing.T) {
+	cache := NewEventCache(filepath.Join(t.TempDir(), "zoom", "events.json"))
+	now := time.Now().Truncate(time.Second)
+
+	require.NoError(t, cache.Update([]*calendar.Event{
+		testEvent("standup", "1", now.Add(time.Hour)),
+		testEvent("retro", "1", now.Add(3*time.Hour)),
+		testEvent("planning", "1", now.Add(5*time.Hour)),
+	}, now, now.Add(24*time.Hour)))
+
+	// A later fetch covering the next two hours finds the standup moved and the retro untouched.
+	require.NoError(t, cache.Update([]*calendar.Event{
+		testEvent("standup", "1", now.Add(90*time.Minute)),
+	}, now, now.Add(2*time.Hour)))
+
+	cached, err := cache.Load()
+	require.NoError(t, err)
+	assert.True(t, cached.Age() < time.Minute)
+	assert.Equal(t, []string{"standup", "retro", "planning"}, cachedIDs(cached.Events))
+	assert.Equal(t, now.Add(90*time.Minute).Format(googleCalendarDateTimeFormat), cached.Events[0].Start.DateTime)
+
+	// A fetch of the whole day without the retro means it was cancelled.
+	require.NoError(t, cache.Update([]*calendar.Event{
+		testEvent("standup", "1", now.Add(90*time.Minute)),
+		testEvent("planning", "1", now.Add(5*time.Hour)),
+	}, now, now.Add(24*time.Hour)))
+
+	cached, err = cache.Load()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"standup", "planning"}, cachedIDs(cached.Events))
+}
+
+func TestCachedEvents_Queries(t *testing.T) {
+	now := time.Now()
+	cached := &CachedEvents{
+		FetchedAt: now.Add(-time.Hour),
+		Events: []*calendar.Event{
+			testEvent("over", "1", now.Add(-time.Hour)),
+			testEvent("started", "1", now.Add(-2*time.Minute)),
+			testEvent("soon", "1", now.Add(10*time.Minute)),
+			testEvent("later", "1", now.Add(3*time.Hour)),
+		},
+	}
+
+	assert.Equal(t, []string{"started", "soon"}, cachedIDs(cached.NextEvents(2, DefaultFilter)))
+	assert.Equal(t, []string{"over", "started", "soon"}, cachedIDs(cached.EventsBetween(now.Add(-2*time.Hour), now.Add(time.Hour), DefaultFilter)))
+	assert.InDelta(t, time.Hour.Seconds(), cached.Age().Seconds(), 1)
+}
+
+func TestCachedEvents_InProgress(t *testing.T) {
+	now := time.Now()
+	workshop := testEvent("workshop", "1", now.Add(-20*time.Minute))
+	workshop.End = &calendar.EventDateTime{DateTime: now.Add(100 * time.Minute).Format(googleCalendarDateTimeFormat)}
+	retro := testEvent("retro", "1", now.Add(-time.Hour))
+	retro.End = &calendar.EventDateTime{DateTime: now.Add(-10 * time.Minute).Format(googleCalendarDateTimeFormat)}
+	cached := &CachedEvents{Events: []*calendar.Event{retro, workshop, testEvent("soon", "1", now.Add(10*time.Minute))}}
+
+	assert.Equal(t, []string{"workshop", "soon"}, cachedIDs(cached.NextEvents(5, DefaultFilter)))
+}
+
+func TestEventCache_UpdateInProgress(t *testing.T) {
+	cache := NewEventCache(filepath.Join(t.TempDir(), "zoom", "events.json"))
+	now := time.Now().Truncate(time.Second)
+	workshop := testEvent("workshop", "1", now.Add(-20*time.Minute))
+	workshop.End = &calendar.EventDateTime{DateTime: now.Add(100 * time.Minute).Format(googleCalendarDateTimeFormat)}
+
+	// The workshop started before the fetched range, but it's in progress, so it was fetched again.
+	require.NoError(t, cache.Update([]*calendar.Event{workshop}, now, now.Add(time.Hour)))
+	require.NoError(t, cache.Update([]*calendar.Event{workshop}, now, now.Add(time.Hour)))
+
+	cached, err := cache.Load()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"workshop"}, cachedIDs(cached.Events))
+}
@@ -1,6 +1,7 @@
 package zoom
 
 import (
+	"html"
 	"net/url"
 	"path"
 	"strings"
@@ -46,6 +47,22 @@ func extractZoomCallURL(input string) (*url.URL, bool) {
 	return nil, false
 }
 
+// extractLinks returns the URLs in the input other than Zoom's, without duplicates, in the order they appear.
+// HTML entities are decoded first, since event descriptions are often HTML.
+func extractLinks(input string) []string {
+	links := []string{}
+	seen := map[string]bool{}
+	for _, link := range urlRegexp.FindAllString(html.UnescapeString(input), -1) {
+		u, err := url.Parse(link)
+		if err != nil || seen[link] || strings.HasSuffix(u.Hostname(), ".zoom.us") || u.Hostname() == "zoom.us" {
+			continue
+		}
+		seen[link] = true
+		links = append(links, link)
+	}
+	return links
+}
+
 func extractZoomCallData(input string) (call, bool) {
 	zoomURL, ok := extractZoomCallURL(input)
 	if !ok {
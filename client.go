@@ -3,6 +3,7 @@ package zoom
 import (
 	"context"
 	"net/http"
+	"time"
 
 	"github.com/benbalter/zoom-go/config"
 	"github.com/pkg/errors"
@@ -10,8 +11,19 @@ import (
 	calendar "google.golang.org/api/calendar/v3"
 )
 
+// DefaultTimeout is the longest a request to Google may take, including retries.
+// The zoom command waits as long before falling back to cached meetings.
+const DefaultTimeout = 10 * time.Second
+
 // NewGoogleClient creates a new client using the token from the given provider.
+// Transient failures are retried for up to DefaultTimeout.
 func NewGoogleClient(provider config.Provider) (*http.Client, error) {
+	return NewGoogleClientWithTimeout(provider, DefaultTimeout)
+}
+
+// NewGoogleClientWithTimeout creates a new client using the token from the given provider.
+// Transient failures are retried, but each request gives up after the timeout.
+func NewGoogleClientWithTimeout(provider config.Provider, timeout time.Duration) (*http.Client, error) {
 	conf, err := provider.GoogleClientConfig()
 	if err != nil {
 		return nil, err
@@ -21,16 +33,41 @@ func NewGoogleClient(provider config.Provider) (*http.Client, error) {
 	if err != nil {
 		return nil, err
 	}
-	return conf.Client(context.Background(), token), nil
+
+	// Token refreshes and API calls both go through the retrying transport.
+	retryClient := &http.Client{Transport: NewRetryTransport(http.DefaultTransport)}
+	ctx := context.WithValue(context.Background(), oauth2.HTTPClient, retryClient)
+
+	client := conf.Client(ctx, token)
+	client.Timeout = timeout
+	return client, nil
 }
 
 // NewGoogleCalendarService creates a new Google Calendar service with the credentials in the provider.
 func NewGoogleCalendarService(provider config.Provider) (*calendar.Service, error) {
-	client, err := NewGoogleClient(provider)
+	return NewGoogleCalendarServiceWithTimeout(provider, DefaultTimeout)
+}
+
+// NewGoogleCalendarServiceWithTimeout creates a new Google Calendar service with the credentials in the provider,
+// whose requests give up after the timeout.
+func NewGoogleCalendarServiceWithTimeout(provider config.Provider, timeout time.Duration) (*calendar.Service, error) {
+	client, err := NewGoogleClientWithTimeout(provider, timeout)
 	if err != nil {
 		return nil, err
 	}
-	return calendar.New(client)
+	return newCalendarService(client)
+}
+
+// newCalendarService creates a Google Calendar service which asks for gzipped responses.
+// Go's transport already sends "Accept-Encoding: gzip" and decompresses responses,
+// but Google only compresses them if the User-Agent also contains "gzip".
+func newCalendarService(client *http.Client) (*calendar.Service, error) {
+	service, err := calendar.New(client)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	service.UserAgent = "zoom-go (gzip)"
+	return service, nil
 }
 
 // GoogleCalendarAuthorizationURL returns the authorization URL for the service configured in the provider.
@@ -52,7 +89,7 @@ func HandleGoogleCalendarAuthorization(provider config.Provider, authCode string
 
 	tok, err := conf.Exchange(oauth2.NoContext, authCode)
 	if err != nil {
-		return errors.WithStack(err)
+		return classifyError(err)
 	}
 
 	return provider.StoreGoogleToken(tok)
@@ -0,0 +1,102 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/benbalter/zoom-go"
+	"github.com/benbalter/zoom-go/config"
+)
+
+// runConflicts prints the meetings which overlap, run back-to-back or are in different places without time to travel.
+func runConflicts(args []string) {
+	settings := mustLoadSettings()
+
+	flags := flag.NewFlagSet("zoom conflicts", flag.ExitOnError)
+	format := flags.String("format", "text", "Output format: text or json")
+	today := flags.Bool("today", false, "Check the meetings today")
+	tomorrow := flags.Bool("tomorrow", false, "Check the meetings tomorrow")
+	week := flags.Bool("week", false, "Check the meetings this week")
+	from := flags.String("from", "", "Check the meetings from this time, e.g. 'monday', '2006-01-02 15:04' or '+2h'")
+	to := flags.String("to", "", "Check the meetings until this time, e.g. 'friday', '2006-01-02' or '+3d'")
+	horizon := flags.Duration("horizon", settingsHorizon(settings), "How far ahead to check meetings when no time range is given")
+	travel := flags.Duration("travel", settingsTravelTime(settings), "How long it takes to get between meetings in different places")
+	tz := flags.String("tz", settings.TimeZone, "Time zone to show meeting times in, e.g. Europe/London, instead of the local one")
+	fetch := addFetchFlags(flags, settings)
+	flags.Parse(args)
+
+	if *format != "text" && *format != "json" {
+		fmt.Printf("unknown format %q, expected text or json\n", *format)
+		os.Exit(exitUsage)
+	}
+	loc := mustLoadTimeZone(*tz)
+
+	now := time.Now().In(loc)
+	timeMin, timeMax, ranged, err := listRange(now, *today, *tomorrow, *week, *from, *to)
+	if err != nil {
+		fmt.Printf("invalid time range: %v\n", err)
+		os.Exit(exitUsage)
+	}
+	if !ranged {
+		timeMin, timeMax = now, now.Add(*horizon)
+	}
+
+	events, err := fetchEventsBetween(fetch, timeMin, timeMax)
+	if errors.Is(err, zoom.ErrNoMeetings) {
+		err = nil
+	}
+	if err != nil {
+		fatal("error fetching meetings", err)
+	}
+
+	meetings := newMeetingsIn(events, loc)
+	conflicts := zoom.FindConflicts(meetings, *travel)
+
+	if *format == "json" {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(map[string][]zoom.Conflict{"conflicts": conflicts}); err != nil {
+			fatal("error printing conflicts", errors.WithStack(err))
+		}
+		return
+	}
+
+	if len(conflicts) == 0 {
+		fmt.Println("No conflicts found.")
+		return
+	}
+
+	day := time.Time{}
+	for _, conflict := range conflicts {
+		start := conflict.First.Start.In(loc)
+		if date := zoom.StartOfDay(start); !date.Equal(day) {
+			if !day.IsZero() {
+				fmt.Println()
+			}
+			day = date
+			fmt.Println(day.Format("Monday, January 2"))
+			fmt.Println("=====================================================")
+		}
+		fmt.Printf("%s  %s\n", start.Format("15:04"), conflict)
+	}
+}
+
+// settingsTravelTime returns how long it takes to get between meetings in different places.
+func settingsTravelTime(settings *config.Settings) time.Duration {
+	if settings.TravelTime.Duration > 0 {
+		return settings.TravelTime.Duration
+	}
+	return zoom.DefaultTravelTime
+}
+
+// printConflicts prints a warning about each conflict.
+func printConflicts(conflicts []zoom.Conflict) {
+	for _, conflict := range conflicts {
+		fmt.Printf("Warning: %s.\n", conflict)
+	}
+}
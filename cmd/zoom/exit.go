@@ -0,0 +1,55 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/benbalter/zoom-go"
+	"github.com/benbalter/zoom-go/config"
+)
+
+// Exit codes, so scripts can tell why zoom failed.
+const (
+	exitError           = 1
+	exitUsage           = 2
+	exitNoMeetings      = 3
+	exitUnauthorized    = 4
+	exitQuotaExceeded   = 5
+	exitMalformedConfig = 6
+	exitNetwork         = 7
+)
+
+// exitCode returns the exit code for the category of the error.
+func exitCode(err error) int {
+	switch {
+	case errors.Is(err, zoom.ErrNoMeetings):
+		return exitNoMeetings
+	case errors.Is(err, zoom.ErrUnauthorized), errors.Is(err, config.ErrNoGoogleToken):
+		return exitUnauthorized
+	case errors.Is(err, zoom.ErrQuotaExceeded):
+		return exitQuotaExceeded
+	case errors.Is(err, zoom.ErrMalformedConfig), errors.Is(err, config.ErrNoGoogleClientConfig):
+		return exitMalformedConfig
+	case errors.Is(err, zoom.ErrNetwork):
+		return exitNetwork
+	default:
+		return exitError
+	}
+}
+
+// fatal prints the error and exits with the code for its category.
+// Errors which the user can act on are printed without a stack trace.
+func fatal(message string, err error) {
+	switch code := exitCode(err); code {
+	case exitNoMeetings:
+		fmt.Printf("No Zoom meetings found: %v.\n", err)
+	case exitUnauthorized:
+		fmt.Printf("%s: %v\nYour Google authorization is invalid or was revoked. Delete ~/.config/google/token.json and run zoom again to re-authorize.\n", message, err)
+	case exitQuotaExceeded, exitNetwork, exitMalformedConfig:
+		fmt.Printf("%s: %v\n", message, err)
+	default:
+		fmt.Printf("%s: %+v\n", message, err)
+	}
+	os.Exit(exitCode(err))
+}
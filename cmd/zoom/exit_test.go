@@ -0,0 +1,31 @@
+package main
+
+import (
+	"errors"
+	"testing"
+
+	pkgerrors "github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/benbalter/zoom-go"
+	"github.com/benbalter/zoom-go/config"
+)
+
+func TestExitCode(t *testing.T) {
+	testCases := []struct {
+		err      error
+		expected int
+	}{
+		{errors.New("boom"), exitError},
+		{&zoom.NoMeetingsError{Horizon: zoom.DefaultHorizon}, exitNoMeetings},
+		{&zoom.APIError{Kind: zoom.ErrUnauthorized, Code: 401}, exitUnauthorized},
+		{pkgerrors.Wrap(config.ErrNoGoogleToken, "unable to load token"), exitUnauthorized},
+		{&zoom.APIError{Kind: zoom.ErrQuotaExceeded, Code: 429}, exitQuotaExceeded},
+		{zoom.ErrMalformedConfig, exitMalformedConfig},
+		{config.ErrNoGoogleClientConfig, exitMalformedConfig},
+		{pkgerrors.WithStack(&zoom.APIError{Kind: zoom.ErrNetwork}), exitNetwork},
+	}
+	for _, testCase := range testCases {
+		assert.Equal(t, testCase.expected, exitCode(testCase.err), "%v", testCase.err)
+	}
+}
@@ -0,0 +1,297 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	humanize "github.com/dustin/go-humanize"
+	"google.golang.org/api/calendar/v3"
+
+	"github.com/benbalter/zoom-go"
+	"github.com/benbalter/zoom-go/config"
+)
+
+// defaultFetchTimeout is how long to wait for Google before falling back to the offline cache.
+const defaultFetchTimeout = zoom.DefaultTimeout
+
+// fetchOptions controls where meetings are fetched from.
+type fetchOptions struct {
+	importCredential string
+	offline          bool
+	refresh          bool
+	sync             bool
+	timeout          time.Duration
+	filter           zoom.Filter
+
+	// newSource creates the source to fetch events from with the filter. It defaults to Google Calendar.
+	newSource func(filter zoom.Filter) (zoom.EventSource, error)
+
+	// stale is set once meetings have been read from the cache instead of Google.
+	stale bool
+}
+
+// newFetchOptions returns the options for fetching the meetings the settings include, with their timeout.
+func newFetchOptions(settings *config.Settings) *fetchOptions {
+	timeout := defaultFetchTimeout
+	if settings.Timeout.Duration > 0 {
+		timeout = settings.Timeout.Duration
+	}
+	return &fetchOptions{timeout: timeout, filter: settingsFilter(settings)}
+}
+
+// addFetchFlags registers the flags controlling where meetings are fetched from.
+func addFetchFlags(flags *flag.FlagSet, settings *config.Settings) *fetchOptions {
+	opts := newFetchOptions(settings)
+	flags.BoolVar(&opts.filter.IncludeDeclined, "include-declined", opts.filter.IncludeDeclined, "Include meetings you declined")
+	flags.BoolVar(&opts.filter.IncludeCancelled, "include-cancelled", opts.filter.IncludeCancelled, "Include cancelled meetings")
+	flags.Var((*handlingFlag)(&opts.filter.Handling), "event-types", "Comma-separated kind=handling overrides, e.g. all-day=skip,focus-time=list; handling is join, list or skip")
+	flags.Var((*seriesFlag)(&opts.filter.Series), "series", "Only include the recurring series with this ID or title pattern; may be repeated")
+	flags.Var((*seriesFlag)(&opts.filter.ExcludeSeries), "exclude-series", "Leave out the recurring series with this ID or title pattern; may be repeated")
+	flags.BoolVar(&opts.offline, "offline", false, "Only use the meetings cached by previous runs")
+	flags.BoolVar(&opts.refresh, "refresh", false, "Never fall back to cached meetings if Google can't be reached")
+	flags.DurationVar(&opts.timeout, "timeout", opts.timeout, "How long to wait for Google before falling back to cached meetings")
+	flags.BoolVar(&opts.sync, "sync", settings.Sync, "Keep a local copy of your calendar in sync, only fetching what changed since the last run")
+	return opts
+}
+
+// source returns the source to fetch the events the filter allows from, walking the user through setup if needed.
+func (opts *fetchOptions) source(filter zoom.Filter) (zoom.EventSource, error) {
+	if opts.newSource != nil {
+		return opts.newSource(filter)
+	}
+	service := mustCalendarService(opts.importCredential, opts.timeout)
+	if opts.sync {
+		source, err := zoom.NewDefaultSyncedSource(service)
+		if err != nil {
+			return nil, err
+		}
+		source.Filter = filter
+		return source, nil
+	}
+	source := zoom.NewGoogleSource(service)
+	source.Filter = filter
+	return source, nil
+}
+
+// settingsFilter returns the filter for the meetings the settings include.
+// It exits if the settings configure an unknown kind of event or handling.
+func settingsFilter(settings *config.Settings) zoom.Filter {
+	filter := zoom.Filter{
+		IncludeDeclined:  settings.IncludeDeclined,
+		IncludeCancelled: settings.IncludeCancelled,
+	}
+
+	pairs := []string{}
+	for kind, how := range settings.EventTypes {
+		pairs = append(pairs, kind+"="+how)
+	}
+	sort.Strings(pairs)
+	handling, err := zoom.ParseHandling(strings.Join(pairs, ","))
+	if err != nil {
+		fmt.Printf("Invalid event_types setting: %v\n", err)
+		os.Exit(exitMalformedConfig)
+	}
+	if len(handling) > 0 {
+		filter.Handling = handling
+	}
+
+	filter.Series = mustParseSeriesSetting("series", settings.Series)
+	filter.ExcludeSeries = mustParseSeriesSetting("exclude_series", settings.ExcludeSeries)
+
+	rules := make([]zoom.Rule, 0, len(settings.Rules))
+	for _, rule := range settings.Rules {
+		rules = append(rules, zoom.Rule{
+			Name:         rule.Name,
+			Title:        rule.Title,
+			Organizer:    rule.Organizer,
+			Calendar:     rule.Calendar,
+			MinAttendees: rule.MinAttendees,
+			MaxAttendees: rule.MaxAttendees,
+			Color:        rule.Color,
+			Provider:     rule.Provider,
+			Action:       zoom.RuleAction(rule.Action),
+		})
+	}
+	if filter.Rules, err = zoom.ParseRules(rules); err != nil {
+		fmt.Printf("Invalid rules setting: %v\n", err)
+		os.Exit(exitMalformedConfig)
+	}
+	return filter
+}
+
+// mustParseSeriesSetting parses the series patterns of a setting, exiting if any are invalid.
+func mustParseSeriesSetting(name string, values []string) []zoom.SeriesPattern {
+	patterns := []zoom.SeriesPattern{}
+	for _, value := range values {
+		pattern, err := zoom.ParseSeriesPattern(value)
+		if err != nil {
+			fmt.Printf("Invalid %s setting: %v\n", name, err)
+			os.Exit(exitMalformedConfig)
+		}
+		patterns = append(patterns, pattern)
+	}
+	return patterns
+}
+
+// handlingFlag is a flag.Value for comma-separated kind=handling pairs, which override the configured handling.
+type handlingFlag map[zoom.EventKind]zoom.Handling
+
+func (h *handlingFlag) String() string {
+	if h == nil {
+		return ""
+	}
+	pairs := []string{}
+	for kind, how := range *h {
+		pairs = append(pairs, string(kind)+"="+string(how))
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, ",")
+}
+
+func (h *handlingFlag) Set(value string) error {
+	handling, err := zoom.ParseHandling(value)
+	if err != nil {
+		return err
+	}
+	merged := handlingFlag{}
+	for kind, how := range *h {
+		merged[kind] = how
+	}
+	for kind, how := range handling {
+		merged[kind] = how
+	}
+	*h = merged
+	return nil
+}
+
+// seriesFlag is a flag.Value for series patterns, which adds each pattern given to the configured ones.
+type seriesFlag []zoom.SeriesPattern
+
+func (s *seriesFlag) String() string {
+	if s == nil {
+		return ""
+	}
+	patterns := []string{}
+	for _, pattern := range *s {
+		patterns = append(patterns, pattern.String())
+	}
+	return strings.Join(patterns, ", ")
+}
+
+func (s *seriesFlag) Set(value string) error {
+	pattern, err := zoom.ParseSeriesPattern(value)
+	if err != nil {
+		return err
+	}
+	*s = append(*s, pattern)
+	return nil
+}
+
+// fetchFunc fetches events from the source, returning the range of start times the result is authoritative for.
+// The source returns every meeting, so the cache is complete, and the meetings are filtered afterwards.
+type fetchFunc func(source zoom.EventSource) (events []*calendar.Event, from, to time.Time, err error)
+
+// fetchEvents fetches events from Google, caches them, and returns those fromCache selects from them.
+// If Google can't be reached or times out, or -offline is set, the events are read from the cache instead
+// and labelled as stale on stderr. Other errors, like revoked authorization, are returned so they're reported
+// rather than hidden behind out of date meetings.
+func fetchEvents(opts *fetchOptions, fetch fetchFunc, fromCache func(*zoom.CachedEvents) []*calendar.Event) ([]*calendar.Event, error) {
+	cache, err := zoom.NewDefaultEventCache()
+	if err != nil {
+		return nil, err
+	}
+
+	if opts.offline {
+		cached, err := cache.Load()
+		if err != nil {
+			return nil, err
+		}
+		fmt.Fprintf(os.Stderr, "Showing meetings cached %s.\n", humanize.Time(cached.FetchedAt))
+		opts.stale = true
+		return fromCache(cached), nil
+	}
+
+	source, err := opts.source(zoom.AllMeetingsFilter)
+	if err != nil {
+		return nil, err
+	}
+
+	events, from, to, err := fetch(source)
+	if err == nil || errors.Is(err, zoom.ErrNoMeetings) {
+		// A zero range, e.g. from a search, isn't authoritative for any meetings, so there's nothing to cache,
+		// and the events are kept in the order they were found in.
+		if from.IsZero() && to.IsZero() {
+			return allowedEvents(events, opts.filter), err
+		}
+		if cacheErr := cache.Update(events, from, to); cacheErr != nil {
+			fmt.Fprintf(os.Stderr, "warning: unable to cache meetings: %v\n", cacheErr)
+		}
+		return fromCache(&zoom.CachedEvents{Events: events}), err
+	}
+
+	if opts.refresh || !errors.Is(err, zoom.ErrNetwork) {
+		return nil, err
+	}
+
+	cached, cacheErr := cache.Load()
+	if cacheErr != nil {
+		return nil, err
+	}
+	fmt.Fprintf(os.Stderr, "Unable to reach Google Calendar (%v).\nShowing meetings cached %s, which may be out of date.\n", err, humanize.Time(cached.FetchedAt))
+	opts.stale = true
+	return fromCache(cached), nil
+}
+
+// fetchNextEvents fetches the next N Zoom meetings within the horizon.
+// Like NextEventsWithin, it returns a *NoMeetingsError if there are none.
+func fetchNextEvents(opts *fetchOptions, count int, horizon time.Duration) ([]*calendar.Event, error) {
+	next := func(cached *zoom.CachedEvents) []*calendar.Event {
+		return cached.NextEvents(count, opts.filter)
+	}
+	events, err := fetchEvents(opts, func(source zoom.EventSource) ([]*calendar.Event, time.Time, time.Time, error) {
+		now := time.Now()
+		from, to := now.Add(-5*time.Minute), now.Add(horizon)
+
+		// Meetings the filter leaves out are fetched too, so keep fetching more until there are enough left.
+		for fetchCount := count; ; fetchCount *= 2 {
+			events, err := source.NextEventsWithin(fetchCount, horizon)
+			if err != nil || len(events) < fetchCount || len(next(&zoom.CachedEvents{Events: events})) >= count {
+				// If the limit was hit, later meetings weren't fetched and are still cached.
+				if len(events) == fetchCount && fetchCount > 0 {
+					to, _ = zoom.MeetingStartTime(events[len(events)-1])
+				}
+				return events, from, to, err
+			}
+		}
+	}, next)
+	if err == nil && len(events) == 0 {
+		err = &zoom.NoMeetingsError{Horizon: horizon}
+	}
+	return events, err
+}
+
+// fetchEventsBetween fetches the Zoom meetings between from and to.
+func fetchEventsBetween(opts *fetchOptions, from, to time.Time) ([]*calendar.Event, error) {
+	return fetchEvents(opts, func(source zoom.EventSource) ([]*calendar.Event, time.Time, time.Time, error) {
+		events, err := source.EventsBetween(from, to)
+		return events, from, to, err
+	}, func(cached *zoom.CachedEvents) []*calendar.Event {
+		return cached.EventsBetween(from, to, opts.filter)
+	})
+}
+
+// allowedEvents returns the events the filter allows, in the same order.
+func allowedEvents(events []*calendar.Event, filter zoom.Filter) []*calendar.Event {
+	allowed := []*calendar.Event{}
+	for _, event := range events {
+		if filter.Allows(event) {
+			allowed = append(allowed, event)
+		}
+	}
+	return allowed
+}
@@ -0,0 +1,105 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/api/calendar/v3"
+
+	"github.com/benbalter/zoom-go"
+)
+
+// fakeSource is an EventSource which returns the events its filter allows, like GoogleSource.
+type fakeSource struct {
+	events []*calendar.Event
+	filter zoom.Filter
+}
+
+func (s *fakeSource) NextEventsWithin(count int, horizon time.Duration) ([]*calendar.Event, error) {
+	return (&zoom.CachedEvents{Events: s.events}).NextEvents(count, s.filter), nil
+}
+
+func (s *fakeSource) EventsBetween(from, to time.Time) ([]*calendar.Event, error) {
+	return (&zoom.CachedEvents{Events: s.events}).EventsBetween(from, to, s.filter), nil
+}
+
+func fetchTestEvent(id string, start time.Time) *calendar.Event {
+	return &calendar.Event{
+		Id:               id + "_" + start.UTC().Format("20060102T150405Z"),
+		RecurringEventId: id,
+		Summary:          id,
+		Location:         "https://jithub.zoom.us/j/1",
+		Start:            &calendar.EventDateTime{DateTime: start.Format(time.RFC3339)},
+		End:              &calendar.EventDateTime{DateTime: start.Add(30 * time.Minute).Format(time.RFC3339)},
+	}
+}
+
+func fetchTestOptions(t *testing.T, events ...*calendar.Event) *fetchOptions {
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	t.Setenv("HOME", t.TempDir())
+	return &fetchOptions{
+		newSource: func(filter zoom.Filter) (zoom.EventSource, error) {
+			return &fakeSource{events: events, filter: filter}, nil
+		},
+	}
+}
+
+func cachedEventIDs(t *testing.T, from, to time.Time) []string {
+	cache, err := zoom.NewDefaultEventCache()
+	require.NoError(t, err)
+	cached, err := cache.Load()
+	require.NoError(t, err)
+
+	ids := []string{}
+	for _, event := range cached.EventsBetween(from, to, zoom.DefaultFilter) {
+		ids = append(ids, event.RecurringEventId)
+	}
+	return ids
+}
+
+func TestFetchEventsBetween_CachesFilteredMeetings(t *testing.T) {
+	now := time.Now().Truncate(time.Second)
+	from, to := now, now.Add(24*time.Hour)
+	declined := fetchTestEvent("planning", now.Add(3*time.Hour))
+	declined.Attendees = []*calendar.EventAttendee{{Email: "me@jithub.com", Self: true, ResponseStatus: "declined"}}
+	opts := fetchTestOptions(t, fetchTestEvent("standup", now.Add(time.Hour)), fetchTestEvent("retro", now.Add(2*time.Hour)), declined)
+
+	standup, err := zoom.ParseSeriesPattern("standup")
+	require.NoError(t, err)
+	opts.filter.Series = []zoom.SeriesPattern{standup}
+
+	events, err := fetchEventsBetween(opts, from, to)
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+	assert.Equal(t, "standup", events[0].Summary)
+
+	// The other meetings are still cached, including the declined one for -include-declined.
+	assert.Equal(t, []string{"standup", "retro"}, cachedEventIDs(t, from, to))
+	opts.offline = true
+	opts.filter = zoom.Filter{IncludeDeclined: true}
+	events, err = fetchEventsBetween(opts, from, to)
+	require.NoError(t, err)
+	assert.Len(t, events, 3)
+}
+
+func TestFetchNextEvents_FetchesMoreWhenFiltered(t *testing.T) {
+	now := time.Now().Truncate(time.Second)
+	opts := fetchTestOptions(t,
+		fetchTestEvent("standup", now.Add(time.Hour)),
+		fetchTestEvent("standup", now.Add(2*time.Hour)),
+		fetchTestEvent("retro", now.Add(3*time.Hour)),
+		fetchTestEvent("planning", now.Add(4*time.Hour)),
+	)
+
+	standup, err := zoom.ParseSeriesPattern("standup")
+	require.NoError(t, err)
+	opts.filter.ExcludeSeries = []zoom.SeriesPattern{standup}
+
+	events, err := fetchNextEvents(opts, 1, 24*time.Hour)
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+	assert.Equal(t, "retro", events[0].Summary)
+	assert.Equal(t, []string{"standup", "standup", "retro", "planning"}, cachedEventIDs(t, now, now.Add(24*time.Hour)))
+}
@@ -0,0 +1,45 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"time"
+
+	"github.com/skratchdot/open-golang/open"
+
+	"github.com/benbalter/zoom-go"
+	"github.com/benbalter/zoom-go/config"
+)
+
+// settingsHooks returns the meeting hooks the settings configure, exiting if any are invalid.
+func settingsHooks(settings *config.Settings) []zoom.MeetingHook {
+	hooks := make([]zoom.MeetingHook, 0, len(settings.Hooks))
+	for _, hook := range settings.Hooks {
+		hooks = append(hooks, zoom.MeetingHook{
+			Event:     zoom.MeetingHookEvent(hook.Event),
+			Command:   hook.Command,
+			Timeout:   hook.Timeout.Duration,
+			OnFailure: zoom.HookFailurePolicy(hook.OnFailure),
+		})
+	}
+	hooks, err := zoom.ParseMeetingHooks(hooks)
+	if err != nil {
+		fmt.Printf("Invalid hooks setting: %v\n", err)
+		os.Exit(exitMalformedConfig)
+	}
+	return hooks
+}
+
+// openMeeting runs the before-join hooks and opens the meeting, unless a hook aborts.
+func openMeeting(settings *config.Settings, meeting zoom.Meeting) {
+	logger := log.New(os.Stderr, "warning: ", 0)
+	var aborted *zoom.HookAbortedError
+	err := zoom.JoinMeeting(context.Background(), zoom.OpenerFunc(open.Run), settingsHooks(settings), meeting, time.Now(), logger)
+	if errors.As(err, &aborted) {
+		fmt.Printf("Not opening the meeting: %v\n", err)
+		os.Exit(1)
+	}
+}
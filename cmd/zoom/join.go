@@ -0,0 +1,89 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"google.golang.org/api/calendar/v3"
+
+	"github.com/benbalter/zoom-go"
+)
+
+// runJoin searches for a meeting by title, organizer, attendee or description and opens it.
+func runJoin(args []string) {
+	settings := mustLoadSettings()
+
+	flags := flag.NewFlagSet("zoom join", flag.ExitOnError)
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "Usage: zoom join [flags] <query>")
+		flags.PrintDefaults()
+	}
+	horizon := flags.Duration("horizon", settingsHorizon(settings), "How far ahead to look for meetings")
+	pick := flags.String("pick", pickAsk, "How to choose between several matching meetings: ask, or first to join the best match")
+	tz := flags.String("tz", settings.TimeZone, "Time zone to show meeting times in, e.g. Europe/London, instead of the local one")
+	fetch := addFetchFlags(flags, settings)
+	flags.Parse(args)
+	mustValidatePick(*pick)
+	loc := mustLoadTimeZone(*tz)
+
+	query := strings.TrimSpace(strings.Join(flags.Args(), " "))
+	if query == "" {
+		flags.Usage()
+		os.Exit(exitUsage)
+	}
+
+	events, err := searchEvents(fetch, query, time.Now(), *horizon)
+	if err != nil {
+		fatal("error searching meetings", err)
+	}
+
+	if len(events) == 0 {
+		fmt.Printf("No meetings match %q.\n", query)
+		os.Exit(exitNoMeetings)
+	}
+
+	meetings := newMeetingsIn(events, loc)
+	meeting, ok, err := pickMeeting(meetings, *pick)
+	if err != nil {
+		fatal("error picking meeting", err)
+	}
+	if !ok {
+		return
+	}
+
+	fmt.Printf("Opening %q at %s: %s...\n", meeting.Title, meeting.Start.In(loc).Format(time.Kitchen), meeting.URL)
+	openMeeting(settings, meeting)
+}
+
+// searchEvents returns the Zoom meetings within the horizon which match the query, best matches first.
+// Meetings in progress are found, since they end after now, but meetings which have already ended aren't.
+func searchEvents(fetch *fetchOptions, query string, now time.Time, horizon time.Duration) ([]*calendar.Event, error) {
+	from, to := now, now.Add(horizon)
+	return fetchEvents(fetch, func(source zoom.EventSource) ([]*calendar.Event, time.Time, time.Time, error) {
+		meetings, err := zoom.SearchMeetings(source, query, from, to)
+		return meetingEvents(meetings), time.Time{}, time.Time{}, err
+	}, func(cached *zoom.CachedEvents) []*calendar.Event {
+		return meetingEvents(zoom.MatchMeetings(zoom.NewMeetings(cached.EventsBetween(from, to, fetch.filter)), query))
+	})
+}
+
+// newMeetingsIn extracts the meeting details from each event, showing their times in the time zone.
+func newMeetingsIn(events []*calendar.Event, loc *time.Location) []zoom.Meeting {
+	meetings := zoom.NewMeetings(events)
+	for i := range meetings {
+		meetings[i] = meetings[i].In(loc)
+	}
+	return meetings
+}
+
+// meetingEvents returns the calendar event of each meeting.
+func meetingEvents(meetings []zoom.Meeting) []*calendar.Event {
+	events := make([]*calendar.Event, 0, len(meetings))
+	for _, meeting := range meetings {
+		events = append(events, meeting.Event)
+	}
+	return events
+}
@@ -0,0 +1,31 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSearchEvents_SkipsEndedMeetings(t *testing.T) {
+	now := time.Now().Truncate(time.Second)
+	opts := fetchTestOptions(t,
+		fetchTestEvent("standup", now.Add(-50*time.Minute)),
+		fetchTestEvent("standup", now.Add(-10*time.Minute)),
+		fetchTestEvent("standup", now.Add(time.Hour)),
+	)
+
+	events, err := searchEvents(opts, "standup", now, 24*time.Hour)
+	require.NoError(t, err)
+
+	// The standup which ended 20 minutes ago isn't found, but the one in progress is.
+	starts := []string{}
+	for _, event := range events {
+		starts = append(starts, event.Start.DateTime)
+	}
+	assert.ElementsMatch(t, []string{
+		now.Add(-10 * time.Minute).Format(time.RFC3339),
+		now.Add(time.Hour).Format(time.RFC3339),
+	}, starts)
+}
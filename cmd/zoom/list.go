@@ -0,0 +1,148 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"google.golang.org/api/calendar/v3"
+
+	"github.com/benbalter/zoom-go"
+)
+
+// runList prints or exports upcoming meetings without opening any of them.
+func runList(args []string) {
+	settings := mustLoadSettings()
+
+	flags := flag.NewFlagSet("zoom list", flag.ExitOnError)
+	count := flags.Int("count", 10, "Number of calendar events to list when no time range is given")
+	format := flags.String("format", "text", "Output format: text, "+strings.Join(zoom.ExportFormats, ", "))
+	templateText := flags.String("template", settings.Template, "Go text/template used to print each meeting with -format=text")
+	today := flags.Bool("today", false, "List the meetings today")
+	tomorrow := flags.Bool("tomorrow", false, "List the meetings tomorrow")
+	week := flags.Bool("week", false, "List the meetings this week")
+	from := flags.String("from", "", "List the meetings from this time, e.g. 'monday', '2006-01-02 15:04' or '+2h'")
+	to := flags.String("to", "", "List the meetings until this time, e.g. 'friday', '2006-01-02' or '+3d'")
+	horizon := flags.Duration("horizon", settingsHorizon(settings), "How far ahead to look for meetings when no time range is given")
+	tz := flags.String("tz", settings.TimeZone, "Time zone to show meeting times in, e.g. Europe/London, instead of the local one")
+	fetch := addFetchFlags(flags, settings)
+	flags.Parse(args)
+
+	tmpl := mustParseTemplate(*templateText)
+	loc := mustLoadTimeZone(*tz)
+
+	timeMin, timeMax, ranged, err := listRange(time.Now().In(loc), *today, *tomorrow, *week, *from, *to)
+	if err != nil {
+		fmt.Printf("invalid time range: %v\n", err)
+		os.Exit(exitUsage)
+	}
+
+	var events []*calendar.Event
+	if ranged {
+		events, err = fetchEventsBetween(fetch, timeMin, timeMax)
+	} else {
+		events, err = fetchNextEvents(fetch, *count, *horizon)
+	}
+	if errors.Is(err, zoom.ErrNoMeetings) {
+		err = nil
+	}
+	if err != nil {
+		fatal("error fetching meetings", err)
+	}
+
+	meetings := newMeetingsIn(events, loc)
+	meetings = annotateSeries(fetch, meetings, true)
+
+	if *format != "text" {
+		if err := zoom.Export(os.Stdout, *format, meetings, settingsTravelTime(settings)); err != nil {
+			fatal("error exporting meetings", err)
+		}
+		return
+	}
+
+	if len(meetings) == 0 {
+		fmt.Println("No meetings found.")
+		return
+	}
+
+	conflicts := zoom.FindConflicts(meetings, settingsTravelTime(settings))
+	for i, day := range zoom.GroupByDay(meetings, loc) {
+		if i > 0 {
+			fmt.Println()
+		}
+		if day.Date.IsZero() {
+			fmt.Println("Unscheduled")
+		} else {
+			fmt.Println(day.Date.Format("Monday, January 2"))
+		}
+		fmt.Println("=====================================================")
+
+		for _, meeting := range day.Meetings {
+			if err := printMeeting(tmpl, meeting); err != nil {
+				fatal("error printing meeting", err)
+			}
+			printSeriesWarning(meeting)
+			// Each conflict is printed once, after the meeting which starts first.
+			for _, conflict := range conflicts {
+				if conflict.First.ID == meeting.ID && conflict.First.Start.Equal(meeting.Start) {
+					printConflicts([]zoom.Conflict{conflict})
+				}
+			}
+		}
+	}
+}
+
+// listRange converts the time range flags into the range to list.
+// ranged is false if no time range was requested. Only one of -today, -tomorrow, -week or -from/-to can be given.
+func listRange(now time.Time, today, tomorrow, week bool, from, to string) (timeMin, timeMax time.Time, ranged bool, err error) {
+	ranges := 0
+	for _, given := range []bool{today, tomorrow, week, from != "" || to != ""} {
+		if given {
+			ranges++
+		}
+	}
+	if ranges > 1 {
+		return time.Time{}, time.Time{}, false, errors.New("only one of -today, -tomorrow, -week or -from/-to can be given")
+	}
+
+	switch {
+	case today:
+		timeMin, timeMax = zoom.DayRange(now)
+		return timeMin, timeMax, true, nil
+	case tomorrow:
+		timeMin, timeMax = zoom.DayRange(now.AddDate(0, 0, 1))
+		return timeMin, timeMax, true, nil
+	case week:
+		timeMin, timeMax = zoom.WeekRange(now)
+		return timeMin, timeMax, true, nil
+	case from == "" && to == "":
+		return time.Time{}, time.Time{}, false, nil
+	}
+
+	timeMin = now
+	if from != "" {
+		if timeMin, _, err = zoom.ParseDate(from, now); err != nil {
+			return time.Time{}, time.Time{}, false, err
+		}
+	}
+
+	timeMax = zoom.StartOfDay(timeMin).AddDate(0, 0, 1)
+	if to != "" {
+		var dateOnly bool
+		if timeMax, dateOnly, err = zoom.ParseDate(to, now); err != nil {
+			return time.Time{}, time.Time{}, false, err
+		}
+		// A day given as the end of the range includes the whole day.
+		if dateOnly {
+			timeMax = timeMax.AddDate(0, 0, 1)
+		}
+	}
+
+	if !timeMax.After(timeMin) {
+		return time.Time{}, time.Time{}, false, errors.Errorf("%s is not after %s", timeMax.Format(time.RFC1123), timeMin.Format(time.RFC1123))
+	}
+	return timeMin, timeMax, true, nil
+}
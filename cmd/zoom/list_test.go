@@ -0,0 +1,51 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestListRange(t *testing.T) {
+	// A Wednesday afternoon.
+	now := time.Date(2018, time.October, 10, 15, 4, 0, 0, time.UTC)
+	day := func(d int) time.Time { return time.Date(2018, time.October, d, 0, 0, 0, 0, time.UTC) }
+
+	testCases := []struct {
+		name                 string
+		today, tomorrow      bool
+		week                 bool
+		from, to             string
+		timeMin, timeMax     time.Time
+		ranged, expectsError bool
+	}{
+		{name: "none"},
+		{name: "today", today: true, timeMin: day(10), timeMax: day(11), ranged: true},
+		{name: "tomorrow", tomorrow: true, timeMin: day(11), timeMax: day(12), ranged: true},
+		{name: "week", week: true, timeMin: day(8), timeMax: day(15), ranged: true},
+		{name: "from a day", from: "2018-10-12", timeMin: day(12), timeMax: day(13), ranged: true},
+		{name: "to a day", to: "friday", timeMin: now, timeMax: day(13), ranged: true},
+		{name: "from and to", from: "monday", to: "tuesday", timeMin: day(15), timeMax: day(17), ranged: true},
+		{name: "to an instant", from: "today", to: "2018-10-10 17:00", timeMin: day(10), timeMax: now.Add(116 * time.Minute), ranged: true},
+		{name: "backwards", from: "friday", to: "today", expectsError: true},
+		{name: "invalid", from: "someday", expectsError: true},
+		{name: "today and week", today: true, week: true, expectsError: true},
+		{name: "tomorrow and from", tomorrow: true, from: "monday", expectsError: true},
+		{name: "today and to", today: true, to: "friday", expectsError: true},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			timeMin, timeMax, ranged, err := listRange(now, testCase.today, testCase.tomorrow, testCase.week, testCase.from, testCase.to)
+			if testCase.expectsError {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, testCase.ranged, ranged)
+			assert.True(t, testCase.timeMin.Equal(timeMin), "timeMin %s, expected %s", timeMin, testCase.timeMin)
+			assert.True(t, testCase.timeMax.Equal(timeMax), "timeMax %s, expected %s", timeMax, testCase.timeMax)
+		})
+	}
+}
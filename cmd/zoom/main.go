@@ -12,158 +12,210 @@
 //     zoom -import=$HOME/Downloads/google_credentials.json
 //
 // Then, you can run the zoom command without any issue.
+//
+// To list or export your upcoming meetings, run:
+//     zoom list -count=10 -format=markdown
+//
+// To join a meeting which isn't the next one, search for it by title, organizer, attendee or description:
+//     zoom join design review
+//
+// To find meetings which overlap or leave no time to get from one to the next, run:
+//     zoom conflicts -week
+//
+// To see how much of your week is spent in meetings, and with whom, run:
+//     zoom report -week
+//
+// To create a notes file for the meeting in progress or the next one, run:
+//     zoom notes
+//
+// To check which meetings the rules in your settings include, exclude or never open automatically, run:
+//     zoom rules test -week
+//
+// To automatically open each meeting a minute before it starts, run:
+//     zoom watch
+//
+// To let other tools query your meetings over a local REST API, run:
+//     zoom serve -addr=localhost:8765
+//
+// To run hooks, like an "on air" light, when you join or leave meetings, run:
+//     zoom presence -webhook=https://example.com/hook -command='say $ZOOM_PRESENCE'
 package main
 
 import (
+	"bytes"
 	"flag"
 	"fmt"
 	"os"
+	"strings"
+	"text/template"
 	"time"
 
 	"github.com/pkg/errors"
-	"github.com/skratchdot/open-golang/open"
-	"google.golang.org/api/calendar/v3"
 
 	"github.com/benbalter/zoom-go"
-	"github.com/benbalter/zoom-go/config"
 )
 
-func printSetupInstructions() {
-	fmt.Print(`In order to use Zoom Launcher, you need to create an OAuth app and authorize it to access your calendar.
-You can do it in four, not-so-easy steps:
-
-1. Create a new project
-	1. Go to https://console.developers.google.com
-	2. Switch to your work account if need be (top right)
-	3. Create a new project dropdown, top left next to your domain
-2. Grant the project Calendar API access
-	1. Click "Enable API"
-	2. Type "Calendar" in the search box
-	3. Click "Calendar API"
-	4. Click "Enable"
-3. Grab your credentials
-	1. Click "Credentials" on the left side
-	2. Create a new OAuth credential with type "other"
-	3. Download the credential to ~/.config/google/client_secrets.json (icon, right side)
-4. Run 'zoom -import=Downloads/client_secrets.json' and follow the instructions to authorize the app.
-`)
-}
-
-func importGoogleClientConfig(provider config.Provider, filename string) error {
-	conf, err := config.ReadGoogleClientConfigFromFile(filename)
-	if err != nil {
-		return err
-	}
-
-	return provider.StoreGoogleClientConfig(conf)
-}
-
-func authorizeAccount(provider config.Provider) error {
-	authURL, err := zoom.GoogleCalendarAuthorizationURL(provider)
-	if err != nil {
-		return err
-	}
-
-	fmt.Println("Your browser is about to open. When it does, please authorize the application when prompted and paste the token it gives you below.")
-	time.Sleep(5 * time.Second)
-	open.Run(authURL)
-
-	fmt.Print("Authorization token: ")
-	var authCode string
-	if _, err := fmt.Scan(&authCode); err != nil {
-		return errors.WithStack(err)
-	}
-	return zoom.HandleGoogleCalendarAuthorization(provider, authCode)
-}
-
 func main() {
-	provider, err := config.NewFileProvider()
-	if err != nil {
-		fmt.Printf("unable to create file configuration provider: %+v\n", err)
-		os.Exit(1)
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case "list":
+			runList(os.Args[2:])
+			return
+		case "watch":
+			runWatch(os.Args[2:])
+			return
+		case "serve":
+			runServe(os.Args[2:])
+			return
+		case "presence":
+			runPresence(os.Args[2:])
+			return
+		case "join":
+			runJoin(os.Args[2:])
+			return
+		case "conflicts":
+			runConflicts(os.Args[2:])
+			return
+		case "report":
+			runReport(os.Args[2:])
+			return
+		case "rules":
+			runRules(os.Args[2:])
+			return
+		case "notes":
+			runNotes(os.Args[2:])
+			return
+		}
 	}
 
-	count := flag.Int("count", 1, "Number of calendar events to print")
-	importCredential := flag.String("import", "", "Full path to your downloaded Google OAuth2 client_secret JSON file")
-	flag.Parse()
+	runNext(os.Args[1:])
+}
 
-	if importCredential != nil && *importCredential != "" {
-		fmt.Printf("Importing credentials from %q...\n", *importCredential)
-		if err := importGoogleClientConfig(provider, *importCredential); err != nil {
-			fmt.Printf("error importing credentials: %+v\n", err)
-		}
-	}
+// pickLookahead is how many meetings runNext fetches at least, to find every meeting starting at the same time.
+const pickLookahead = 5
 
-	if !provider.GoogleClientConfigExists() {
-		printSetupInstructions()
-		os.Exit(1)
-	}
+// runNext prints the next meetings and opens the first one if it's about to start.
+// If several meetings are about to start, the user picks which one to open.
+func runNext(args []string) {
+	settings := mustLoadSettings()
 
-	if !provider.GoogleTokenExists() {
-		if err := authorizeAccount(provider); err != nil {
-			fmt.Printf("error authorizing: %+v\n", err)
-			os.Exit(1)
-		}
-		fmt.Println("Stored credentials.")
+	pickDefault := pickAsk
+	if settings.Pick != "" {
+		pickDefault = settings.Pick
 	}
 
-	calendar, err := zoom.NewGoogleCalendarService(provider)
-	if err != nil {
-		fmt.Printf("error creating google calendar client: %+v\n", err)
-		os.Exit(1)
+	flags := flag.NewFlagSet("zoom", flag.ExitOnError)
+	count := flags.Int("count", 1, "Number of calendar events to print")
+	importCredential := flags.String("import", "", "Full path to your downloaded Google OAuth2 client_secret JSON file")
+	templateText := flags.String("template", settings.Template, "Go text/template used to print each meeting, e.g. '{{.Title}} {{.Until}}'")
+	horizon := flags.Duration("horizon", settingsHorizon(settings), "How far ahead to look for meetings")
+	pick := flags.String("pick", pickDefault, "How to choose between meetings starting at the same time: ask, first, most-attendees, accepted or organized-by-me")
+	tz := flags.String("tz", settings.TimeZone, "Time zone to show meeting times in, e.g. Europe/London, instead of the local one")
+	fetch := addFetchFlags(flags, settings)
+	flags.Parse(args)
+
+	tmpl := mustParseTemplate(*templateText)
+	loc := mustLoadTimeZone(*tz)
+	fetch.importCredential = *importCredential
+	mustValidatePick(*pick)
+
+	fetchCount := *count
+	if fetchCount < pickLookahead {
+		fetchCount = pickLookahead
 	}
-
-	meetings, err := zoom.NextEvents(calendar, *count)
+	events, err := fetchNextEvents(fetch, fetchCount, *horizon)
 	if err != nil {
-		fmt.Printf("error fetching next meetings: %+v\n", err)
-		os.Exit(1)
+		fatal("error fetching next meetings", err)
 	}
 
-	if len(meetings) == 0 {
+	if len(events) == 0 {
 		fmt.Println("No upcoming events found.")
 		return
 	}
 
-	for _, meeting := range meetings {
-		printMeeting(meeting)
+	meetings := newMeetingsIn(events, loc)
+	// Only the meetings printed are numbered in their series, from the history cached by zoom list, so printing
+	// the next meeting doesn't wait for Google to list every earlier instance.
+	printed := *count
+	if printed > len(meetings) {
+		printed = len(meetings)
+	}
+	copy(meetings, annotateSeries(fetch, meetings[:printed], false))
+
+	conflicts := zoom.FindConflicts(meetings, settingsTravelTime(settings))
+	for i, meeting := range meetings {
+		if i == *count {
+			break
+		}
+		if err := printMeeting(tmpl, meeting); err != nil {
+			fatal("error printing meeting", err)
+		}
+		printSeriesWarning(meeting)
+		printConflicts(zoom.ConflictsInvolving(conflicts, meeting))
 		if *count > 1 {
 			fmt.Println("_____________________________________________________")
 		}
 	}
 
-	firstMeeting := meetings[0]
-	if zoom.IsMeetingSoon(firstMeeting) {
-		url, ok := zoom.MeetingURLFromEvent(firstMeeting)
-		if !ok {
+	joinable := zoom.JoinableMeetings(meetings, time.Now(), fetch.filter)
+	if len(joinable) == 0 {
+		// Meetings the filter doesn't join automatically, like all-day events, are only listed.
+		switch {
+		case !meetings[0].Soon():
+		case meetings[0].URL == "":
 			fmt.Println("No Zoom URL found in the meeting.")
 			os.Exit(1)
+		default:
+			fmt.Printf("Not opening %q automatically.\n", meetings[0].Title)
 		}
-		fmt.Printf("Opening %s...\n", url)
-		_ = open.Run(url.String())
+		return
 	}
-}
 
-func printMeeting(meeting *calendar.Event) {
-	fmt.Println(zoom.MeetingSummary(meeting))
-
-	startTime, err := zoom.MeetingStartTime(meeting)
+	meeting, ok, err := pickMeeting(joinable, *pick)
 	if err != nil {
-		fmt.Println("This meeting does not have a start time...?")
-		return
+		fatal("error picking meeting", err)
 	}
-	if time.Until(startTime) < 0 {
-		fmt.Printf("It started %s.\n", zoom.HumanizedStartTime(meeting))
-	} else {
-		fmt.Printf("It starts %s.\n", zoom.HumanizedStartTime(meeting))
+	if !ok {
+		if *pick != pickAsk {
+			fmt.Printf("Several meetings are starting, but none match the %s policy.\n", *pick)
+		}
+		return
 	}
 
-	fmt.Printf("Calendar event URL: %s\n\n", meeting.HtmlLink)
+	fmt.Printf("Opening %s...\n", meeting.URL)
+	openMeeting(settings, meeting)
+}
 
-	url, ok := zoom.MeetingURLFromEvent(meeting)
-	if !ok {
-		fmt.Println("No Zoom URL found in the meeting.")
-		os.Exit(1)
+func mustParseTemplate(text string) *template.Template {
+	if text == "" {
+		text = zoom.DefaultTemplate
+	}
+	tmpl, err := zoom.ParseTemplate(text)
+	if err != nil {
+		fmt.Printf("invalid template: %v\n", err)
+		os.Exit(exitUsage)
+	}
+	return tmpl
+}
+
+// mustLoadTimeZone returns the time zone to show meeting times in, exiting if it's unknown.
+func mustLoadTimeZone(name string) *time.Location {
+	loc, err := zoom.LoadTimeZone(name)
+	if err != nil {
+		fmt.Printf("%v\n", err)
+		os.Exit(exitUsage)
 	}
+	return loc
+}
 
-	fmt.Printf("Zoom URL: %s\n", url)
+func printMeeting(tmpl *template.Template, meeting zoom.Meeting) error {
+	var output bytes.Buffer
+	if err := zoom.RenderMeeting(&output, tmpl, meeting); err != nil {
+		return err
+	}
+	if !strings.HasSuffix(output.String(), "\n") {
+		output.WriteString("\n")
+	}
+	_, err := output.WriteTo(os.Stdout)
+	return errors.WithStack(err)
 }
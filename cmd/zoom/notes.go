@@ -0,0 +1,87 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+
+	"github.com/pkg/errors"
+	"github.com/skratchdot/open-golang/open"
+
+	"github.com/benbalter/zoom-go"
+)
+
+// runNotes creates a notes file for the meeting in progress or the next one, and opens it.
+// Existing notes are opened as they are.
+func runNotes(args []string) {
+	settings := mustLoadSettings()
+
+	pathDefault := zoom.DefaultNotesPath
+	if settings.NotesPath != "" {
+		pathDefault = settings.NotesPath
+	}
+
+	flags := flag.NewFlagSet("zoom notes", flag.ExitOnError)
+	pathPattern := flags.String("path", pathDefault, "Go text/template for the notes file's path, e.g. '~/Notes/{{.Date}} {{.Title | filename}}.md'")
+	templateFile := flags.String("template", settings.NotesTemplate, "File containing the Go text/template notes are created from")
+	openNotes := flags.Bool("open", true, "Open the notes file")
+	horizon := flags.Duration("horizon", settingsHorizon(settings), "How far ahead to look for the next meeting")
+	tz := flags.String("tz", settings.TimeZone, "Time zone to write meeting times in, e.g. Europe/London, instead of the local one")
+	fetch := addFetchFlags(flags, settings)
+	flags.Parse(args)
+
+	templateText := zoom.DefaultNotesTemplate
+	if *templateFile != "" {
+		path, err := zoom.ExpandHome(*templateFile)
+		if err != nil {
+			fatal("error finding notes template", err)
+		}
+		b, err := ioutil.ReadFile(path)
+		if err != nil {
+			fmt.Printf("unable to read notes template: %v\n", err)
+			os.Exit(exitUsage)
+		}
+		templateText = string(b)
+	}
+	tmpl := mustParseTemplate(templateText)
+	loc := mustLoadTimeZone(*tz)
+
+	events, err := fetchNextEvents(fetch, pickLookahead, *horizon)
+	if err != nil && !errors.Is(err, zoom.ErrNoMeetings) {
+		fatal("error fetching next meetings", err)
+	}
+
+	// Notes are for the meeting in progress, or else the next one. All-day events aren't meetings to take notes in.
+	var meeting *zoom.Meeting
+	for _, candidate := range zoom.NewMeetings(events) {
+		if !candidate.AllDay {
+			candidate = candidate.In(loc)
+			meeting = &candidate
+			break
+		}
+	}
+	if meeting == nil {
+		fmt.Println("No upcoming meetings found.")
+		os.Exit(exitNoMeetings)
+	}
+
+	path, err := zoom.NotesPath(*pathPattern, *meeting)
+	if err != nil {
+		fmt.Printf("%v\n", err)
+		os.Exit(exitUsage)
+	}
+	created, err := zoom.WriteNotes(path, tmpl, *meeting)
+	if err != nil {
+		fatal("error creating notes", err)
+	}
+	if created {
+		fmt.Printf("Created notes for %q: %s\n", meeting.Title, path)
+	} else {
+		fmt.Printf("Notes for %q already exist: %s\n", meeting.Title, path)
+	}
+
+	if *openNotes {
+		_ = open.Run(path)
+	}
+}
@@ -0,0 +1,99 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/benbalter/zoom-go"
+)
+
+// pickAsk is the -pick value which asks which meeting to join when several are starting.
+const pickAsk = "ask"
+
+// pickMeeting chooses which of the joinable meetings to open. If there are several and the pick policy is
+// pickAsk, the user is asked, with a fuzzy-filtering picker if stdout is a terminal or a numbered prompt otherwise.
+// When stdin isn't a terminal, or another policy is set, the policy chooses without asking.
+func pickMeeting(joinable []zoom.Meeting, pick string) (zoom.Meeting, bool, error) {
+	if len(joinable) <= 1 {
+		meeting, ok := zoom.PickFirst.Pick(joinable)
+		return meeting, ok, nil
+	}
+
+	if pick != pickAsk {
+		policy, err := zoom.ParsePickPolicy(pick)
+		if err != nil {
+			return zoom.Meeting{}, false, err
+		}
+		meeting, ok := policy.Pick(joinable)
+		return meeting, ok, nil
+	}
+
+	if !isTerminal(os.Stdin) {
+		meeting, ok := zoom.PickFirst.Pick(joinable)
+		return meeting, ok, nil
+	}
+
+	if isTerminal(os.Stdout) {
+		if restore, err := makeRaw(); err == nil {
+			meeting, err := zoom.FuzzyPickMeeting(os.Stdin, os.Stdout, joinable)
+			restore()
+			return meeting, err == nil, ignoreCancel(err)
+		}
+	}
+
+	// Keep stdout clean for whatever it's piped to.
+	meeting, err := zoom.PromptMeeting(os.Stdin, os.Stderr, joinable)
+	return meeting, err == nil, ignoreCancel(err)
+}
+
+// mustValidatePick exits with a usage error if pick isn't pickAsk or a known policy.
+func mustValidatePick(pick string) {
+	if pick == pickAsk {
+		return
+	}
+	if _, err := zoom.ParsePickPolicy(pick); err != nil {
+		fmt.Printf("%v\n", err)
+		os.Exit(exitUsage)
+	}
+}
+
+// ignoreCancel returns nil if the user cancelled picking a meeting.
+func ignoreCancel(err error) error {
+	if err == zoom.ErrPickCancelled {
+		return nil
+	}
+	return err
+}
+
+// isTerminal returns true if the file is a terminal.
+func isTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+// makeRaw puts the terminal on stdin into raw mode, so keys are read as they're pressed, using stty.
+// It returns a function which restores the previous mode.
+func makeRaw() (func(), error) {
+	saved, err := stty("-g")
+	if err != nil {
+		return nil, err
+	}
+	if _, err := stty("raw", "-echo"); err != nil {
+		return nil, err
+	}
+	return func() {
+		stty(strings.TrimSpace(saved))
+	}, nil
+}
+
+func stty(args ...string) (string, error) {
+	cmd := exec.Command("stty", args...)
+	cmd.Stdin = os.Stdin
+	output, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("stty %s: %v", strings.Join(args, " "), err)
+	}
+	return string(output), nil
+}
@@ -0,0 +1,97 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"log"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"syscall"
+
+	"github.com/benbalter/zoom-go"
+)
+
+// stringList is a flag.Value which collects each use of a repeatable flag.
+type stringList []string
+
+func (s *stringList) String() string {
+	return strings.Join(*s, ",")
+}
+
+func (s *stringList) Set(value string) error {
+	*s = append(*s, value)
+	return nil
+}
+
+// runPresence runs continuously, firing hooks whenever you become free, have a meeting soon or are in a meeting.
+func runPresence(args []string) {
+	settings := mustLoadSettings()
+
+	soon := zoom.DefaultPresenceSoon
+	if settings.Presence.Soon.Duration > 0 {
+		soon = settings.Presence.Soon.Duration
+	}
+	pollInterval := zoom.DefaultPollInterval
+	if settings.PollInterval.Duration > 0 {
+		pollInterval = settings.PollInterval.Duration
+	}
+	stateFile := settings.Presence.StateFile
+	if stateFile == "" {
+		if dir, err := os.UserCacheDir(); err == nil {
+			stateFile = filepath.Join(dir, "zoom", "presence.json")
+		}
+	}
+
+	flags := flag.NewFlagSet("zoom presence", flag.ExitOnError)
+	fetch := newFetchOptions(settings)
+	var commands, webhooks stringList
+	flags.DurationVar(&soon, "soon", soon, "How long before a meeting starts you count as having a meeting soon")
+	flags.Var(&commands, "command", "Shell command to run on each change, may be repeated")
+	flags.Var(&webhooks, "webhook", "URL to post each change to, may be repeated")
+	webhookSecret := flags.String("webhook-secret", os.Getenv("ZOOM_WEBHOOK_SECRET"), "Secret to sign -webhook payloads with, also read from $ZOOM_WEBHOOK_SECRET")
+	flags.StringVar(&stateFile, "state-file", stateFile, "File to write the current presence to, or empty for none")
+	flags.DurationVar(&pollInterval, "poll", pollInterval, "How often to check the calendar for changes")
+	flags.BoolVar(&fetch.sync, "sync", true, "Keep a local copy of your calendar in sync, only fetching what changed since the last poll")
+	flags.Parse(args)
+
+	source, err := fetch.source(fetch.filter)
+	if err != nil {
+		fatal("error creating calendar source", err)
+	}
+
+	hooks := []zoom.PresenceHook{}
+	if stateFile != "" {
+		hooks = append(hooks, &zoom.StateFileHook{Path: stateFile})
+	}
+	for _, command := range append(settings.Presence.Commands, commands...) {
+		hooks = append(hooks, &zoom.CommandHook{Command: command})
+	}
+	for _, webhook := range settings.Presence.Webhooks {
+		hooks = append(hooks, &zoom.WebhookHook{URL: webhook.URL, Secret: webhook.Secret})
+	}
+	for _, url := range webhooks {
+		hooks = append(hooks, &zoom.WebhookHook{URL: url, Secret: *webhookSecret})
+	}
+
+	logger := log.New(os.Stderr, "", log.LstdFlags)
+	engine := &zoom.PresenceEngine{
+		Source:       source,
+		Hooks:        hooks,
+		Soon:         soon,
+		PollInterval: pollInterval,
+		Logger:       logger,
+		Filter:       fetch.filter,
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	if stateFile != "" {
+		logger.Printf("Writing presence to %s.", stateFile)
+	}
+	logger.Println("Watching your presence. Press Ctrl-C to stop.")
+	engine.Run(ctx)
+	logger.Println("Stopped watching.")
+}
@@ -0,0 +1,60 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/benbalter/zoom-go"
+)
+
+// runReport prints how much time is spent in meetings each day, and by organizer, series, provider and size.
+func runReport(args []string) {
+	settings := mustLoadSettings()
+
+	flags := flag.NewFlagSet("zoom report", flag.ExitOnError)
+	format := flags.String("format", "table", "Output format: "+strings.Join(zoom.ReportFormats, ", "))
+	today := flags.Bool("today", false, "Report on the meetings today")
+	week := flags.Bool("week", false, "Report on the meetings this week (the default)")
+	from := flags.String("from", "", "Report on the meetings from this time, e.g. 'monday', '2006-01-02' or '-7d'")
+	to := flags.String("to", "", "Report on the meetings until this time, e.g. 'friday', '2006-01-02' or '+3d'")
+	hoursText := flags.String("hours", settings.WorkingHours, "Working hours to look for free time in, e.g. 09:00-17:00")
+	tz := flags.String("tz", settings.TimeZone, "Time zone to report days in, e.g. Europe/London, instead of the local one")
+	fetch := addFetchFlags(flags, settings)
+	flags.Parse(args)
+
+	hours, err := zoom.ParseWorkingHours(*hoursText)
+	if err != nil {
+		fmt.Printf("invalid working hours: %v\n", err)
+		os.Exit(exitUsage)
+	}
+	loc := mustLoadTimeZone(*tz)
+
+	// Days start at midnight in the time zone the report is shown in.
+	now := time.Now().In(loc)
+	timeMin, timeMax, ranged, err := listRange(now, *today, false, *week, *from, *to)
+	if err != nil {
+		fmt.Printf("invalid time range: %v\n", err)
+		os.Exit(exitUsage)
+	}
+	if !ranged {
+		timeMin, timeMax = zoom.WeekRange(now)
+	}
+
+	events, err := fetchEventsBetween(fetch, timeMin, timeMax)
+	if errors.Is(err, zoom.ErrNoMeetings) {
+		err = nil
+	}
+	if err != nil {
+		fatal("error fetching meetings", err)
+	}
+
+	report := zoom.NewReport(zoom.NewMeetings(events), timeMin.In(loc), timeMax.In(loc), hours)
+	if err := zoom.WriteReport(os.Stdout, *format, report); err != nil {
+		fatal("error printing report", err)
+	}
+}
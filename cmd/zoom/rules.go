@@ -0,0 +1,88 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/benbalter/zoom-go"
+)
+
+// runRules runs the rules subcommand. Its only subcommand, test, shows what the rules do with upcoming meetings.
+func runRules(args []string) {
+	if len(args) == 0 || args[0] != "test" {
+		fmt.Println("usage: zoom rules test [flags]")
+		os.Exit(exitUsage)
+	}
+	settings := mustLoadSettings()
+
+	flags := flag.NewFlagSet("zoom rules test", flag.ExitOnError)
+	today := flags.Bool("today", false, "Test the meetings today")
+	tomorrow := flags.Bool("tomorrow", false, "Test the meetings tomorrow")
+	week := flags.Bool("week", false, "Test the meetings this week")
+	from := flags.String("from", "", "Test the meetings from this time, e.g. 'monday', '2006-01-02 15:04' or '+2h'")
+	to := flags.String("to", "", "Test the meetings until this time, e.g. 'friday', '2006-01-02' or '+3d'")
+	horizon := flags.Duration("horizon", settingsHorizon(settings), "How far ahead to test meetings when no time range is given")
+	tz := flags.String("tz", settings.TimeZone, "Time zone to show meeting times in, e.g. Europe/London, instead of the local one")
+	fetch := addFetchFlags(flags, settings)
+	flags.Parse(args[1:])
+
+	loc := mustLoadTimeZone(*tz)
+
+	now := time.Now().In(loc)
+	timeMin, timeMax, ranged, err := listRange(now, *today, *tomorrow, *week, *from, *to)
+	if err != nil {
+		fmt.Printf("invalid time range: %v\n", err)
+		os.Exit(exitUsage)
+	}
+	if !ranged {
+		timeMin, timeMax = now, now.Add(*horizon)
+	}
+
+	// The meetings are fetched without the rules, to show what each rule does with them.
+	filter := fetch.filter
+	fetch.filter.Rules = nil
+	events, err := fetchEventsBetween(fetch, timeMin, timeMax)
+	if errors.Is(err, zoom.ErrNoMeetings) {
+		err = nil
+	}
+	if err != nil {
+		fatal("error fetching meetings", err)
+	}
+
+	if len(events) == 0 {
+		fmt.Println("No meetings found.")
+		return
+	}
+
+	output := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(output, "Start\tTitle\tOutcome\tRule")
+	for _, event := range events {
+		meeting := zoom.NewMeeting(event).In(loc)
+
+		outcome := "joined automatically"
+		switch {
+		case !filter.Allows(event):
+			outcome = "excluded"
+		case !filter.AutoJoins(event):
+			outcome = "listed, never joined automatically"
+		}
+		rule := "-"
+		if matched, ok := filter.RuleFor(event); ok {
+			rule = matched.String()
+		}
+
+		start := "unscheduled"
+		if !meeting.Start.IsZero() {
+			start = meeting.Start.In(loc).Format("Mon Jan 2 15:04")
+		}
+		fmt.Fprintf(output, "%s\t%s\t%s\t%s\n", start, meeting.Title, outcome, rule)
+	}
+	if err := output.Flush(); err != nil {
+		fatal("error printing rules", errors.WithStack(err))
+	}
+}
@@ -0,0 +1,69 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/benbalter/zoom-go"
+)
+
+// annotateSeries numbers the meetings in recurring series and notes those whose join URL changed, using the
+// history of each series cached by earlier runs. If update is true, the history is first brought up to date
+// from Google, which only fetches a series' earlier instances the first time it's seen. Offline, or when
+// Google couldn't be reached, the cached history is used as it is.
+func annotateSeries(opts *fetchOptions, meetings []zoom.Meeting, update bool) []zoom.Meeting {
+	// Each series is needed up to its last meeting, which includes all of them.
+	ends := map[string]time.Time{}
+	for _, meeting := range meetings {
+		if meeting.SeriesID != "" && meeting.Start.After(ends[meeting.SeriesID]) {
+			ends[meeting.SeriesID] = meeting.Start
+		}
+	}
+	if len(ends) == 0 {
+		return meetings
+	}
+
+	cache, err := zoom.NewDefaultSeriesCache()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "warning: unable to read the recurring series: %v\n", err)
+		return zoom.AnnotateSeries(meetings, nil)
+	}
+	histories, err := cache.Load()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "warning: unable to read the recurring series: %v\n", err)
+		histories = map[string]zoom.SeriesHistory{}
+	}
+
+	if update && !opts.offline && !opts.stale {
+		source := zoom.NewGoogleSource(mustCalendarService(opts.importCredential, opts.timeout))
+		now := time.Now()
+		for seriesID, end := range ends {
+			history, err := source.UpdateSeriesHistory(seriesID, histories[seriesID], end.Add(time.Second), now)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "warning: unable to fetch the recurring series: %v\n", err)
+				break
+			}
+			histories[seriesID] = history
+		}
+		if err := cache.Save(histories); err != nil {
+			fmt.Fprintf(os.Stderr, "warning: unable to cache the recurring series: %v\n", err)
+		}
+	}
+
+	// Series which haven't been fetched yet aren't numbered.
+	known := map[string]zoom.SeriesHistory{}
+	for seriesID := range ends {
+		if history, ok := histories[seriesID]; ok {
+			known[seriesID] = history
+		}
+	}
+	return zoom.AnnotateSeries(meetings, known)
+}
+
+// printSeriesWarning warns if the meeting's join URL differs from the rest of its series.
+func printSeriesWarning(meeting zoom.Meeting) {
+	if meeting.SeriesURL != "" {
+		fmt.Printf("Warning: this meeting's Zoom URL differs from the rest of its series, which uses %s.\n", meeting.SeriesURL)
+	}
+}
@@ -0,0 +1,114 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/skratchdot/open-golang/open"
+
+	"github.com/benbalter/zoom-go"
+)
+
+// defaultServeAddr is the address zoom serve listens on by default.
+const defaultServeAddr = "localhost:8765"
+
+// runServe serves a local REST API for other tools to query and join meetings.
+func runServe(args []string) {
+	settings := mustLoadSettings()
+
+	addr := defaultServeAddr
+	if settings.ServeAddr != "" {
+		addr = settings.ServeAddr
+	}
+	token := settings.ServeToken
+	if envToken := os.Getenv("ZOOM_SERVE_TOKEN"); envToken != "" {
+		token = envToken
+	}
+
+	flags := flag.NewFlagSet("zoom serve", flag.ExitOnError)
+	fetch := newFetchOptions(settings)
+	flags.StringVar(&addr, "addr", addr, "Local address to listen on")
+	socket := flags.String("socket", "", "Path of a Unix socket to listen on instead of -addr")
+	flags.StringVar(&token, "token", token, "Bearer token clients must send, also read from $ZOOM_SERVE_TOKEN")
+	join := flags.Bool("join", true, "Allow clients to open meetings")
+	horizon := flags.Duration("horizon", settingsHorizon(settings), "How far ahead to look for the next meeting")
+	flags.BoolVar(&fetch.sync, "sync", true, "Keep a local copy of your calendar in sync, only fetching what changed since the last request")
+	flags.Parse(args)
+
+	listener, err := listen(addr, *socket, token)
+	if err != nil {
+		fmt.Printf("%v\n", err)
+		os.Exit(exitUsage)
+	}
+
+	source, err := fetch.source(fetch.filter)
+	if err != nil {
+		fatal("error creating calendar source", err)
+	}
+
+	logger := log.New(os.Stderr, "", log.LstdFlags)
+	handler := &zoom.Server{Source: source, Token: token, Horizon: *horizon, Logger: logger}
+	if *join {
+		handler.Opener = zoom.OpenerFunc(open.Run)
+		handler.Hooks = settingsHooks(settings)
+	}
+
+	server := &http.Server{
+		Handler:      handler,
+		ReadTimeout:  10 * time.Second,
+		WriteTimeout: time.Minute,
+		ErrorLog:     logger,
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		server.Shutdown(shutdownCtx)
+	}()
+
+	logger.Printf("Serving meetings on %s://%s. Press Ctrl-C to stop.", listener.Addr().Network(), listener.Addr())
+	if err := server.Serve(listener); err != nil && err != http.ErrServerClosed {
+		fatal("error serving", err)
+	}
+	logger.Println("Stopped serving.")
+}
+
+// listen listens on the Unix socket if there is one, otherwise on the address.
+// Addresses other than localhost are refused without a token, since anyone who can reach them could join meetings.
+func listen(addr, socket, token string) (net.Listener, error) {
+	if socket != "" {
+		// Remove a socket left behind by a previous run.
+		if info, err := os.Stat(socket); err == nil && info.Mode()&os.ModeSocket != 0 {
+			os.Remove(socket)
+		}
+		// The socket is created with the right permissions, rather than chmodded after, so no one else can
+		// connect in between.
+		restore := privateUmask()
+		listener, err := net.Listen("unix", socket)
+		restore()
+		if err != nil {
+			return nil, err
+		}
+		return listener, os.Chmod(socket, 0600)
+	}
+
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return nil, err
+	}
+	if ip := net.ParseIP(host); host != "localhost" && (ip == nil || !ip.IsLoopback()) && token == "" {
+		return nil, fmt.Errorf("refusing to listen on %s without a -token; use localhost or set a token", addr)
+	}
+	return net.Listen("tcp", addr)
+}
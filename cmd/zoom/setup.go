@@ -0,0 +1,120 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/skratchdot/open-golang/open"
+	"google.golang.org/api/calendar/v3"
+
+	"github.com/benbalter/zoom-go"
+	"github.com/benbalter/zoom-go/config"
+)
+
+func printSetupInstructions() {
+	fmt.Print(`In order to use Zoom Launcher, you need to create an OAuth app and authorize it to access your calendar.
+You can do it in four, not-so-easy steps:
+
+1. Create a new project
+	1. Go to https://console.developers.google.com
+	2. Switch to your work account if need be (top right)
+	3. Create a new project dropdown, top left next to your domain
+2. Grant the project Calendar API access
+	1. Click "Enable API"
+	2. Type "Calendar" in the search box
+	3. Click "Calendar API"
+	4. Click "Enable"
+3. Grab your credentials
+	1. Click "Credentials" on the left side
+	2. Create a new OAuth credential with type "other"
+	3. Download the credential to ~/.config/google/client_secrets.json (icon, right side)
+4. Run 'zoom -import=Downloads/client_secrets.json' and follow the instructions to authorize the app.
+`)
+}
+
+func importGoogleClientConfig(provider config.Provider, filename string) error {
+	conf, err := config.ReadGoogleClientConfigFromFile(filename)
+	if err != nil {
+		return err
+	}
+
+	return provider.StoreGoogleClientConfig(conf)
+}
+
+func authorizeAccount(provider config.Provider) error {
+	authURL, err := zoom.GoogleCalendarAuthorizationURL(provider)
+	if err != nil {
+		return err
+	}
+
+	fmt.Println("Your browser is about to open. When it does, please authorize the application when prompted and paste the token it gives you below.")
+	time.Sleep(5 * time.Second)
+	open.Run(authURL)
+
+	fmt.Print("Authorization token: ")
+	var authCode string
+	if _, err := fmt.Scan(&authCode); err != nil {
+		return errors.WithStack(err)
+	}
+	return zoom.HandleGoogleCalendarAuthorization(provider, authCode)
+}
+
+func loadSettings() (*config.Settings, error) {
+	path, err := config.DefaultSettingsPath()
+	if err != nil {
+		return nil, err
+	}
+	return config.ReadSettingsFromFile(path)
+}
+
+// settingsHorizon returns the configured horizon, or the default if none is configured.
+func settingsHorizon(settings *config.Settings) time.Duration {
+	if settings.Horizon.Duration > 0 {
+		return settings.Horizon.Duration
+	}
+	return zoom.DefaultHorizon
+}
+
+func mustLoadSettings() *config.Settings {
+	settings, err := loadSettings()
+	if err != nil {
+		fatal("unable to read settings", err)
+	}
+	return settings
+}
+
+// mustCalendarService imports credentials if requested, walks the user through setup and
+// authorization if needed, and returns a Google Calendar service.
+func mustCalendarService(importCredential string, timeout time.Duration) *calendar.Service {
+	provider, err := config.NewFileProvider()
+	if err != nil {
+		fatal("unable to create file configuration provider", err)
+	}
+
+	if importCredential != "" {
+		fmt.Printf("Importing credentials from %q...\n", importCredential)
+		if err := importGoogleClientConfig(provider, importCredential); err != nil {
+			fmt.Printf("error importing credentials: %+v\n", err)
+		}
+	}
+
+	if !provider.GoogleClientConfigExists() {
+		printSetupInstructions()
+		os.Exit(exitMalformedConfig)
+	}
+
+	if !provider.GoogleTokenExists() {
+		if err := authorizeAccount(provider); err != nil {
+			fatal("error authorizing", err)
+		}
+		fmt.Println("Stored credentials.")
+	}
+
+	service, err := zoom.NewGoogleCalendarServiceWithTimeout(provider, timeout)
+	if err != nil {
+		fatal("error creating google calendar client", err)
+	}
+	return service
+}
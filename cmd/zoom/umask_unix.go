@@ -0,0 +1,11 @@
+//go:build !windows
+
+package main
+
+import "syscall"
+
+// privateUmask sets the umask so files are only accessible by the user, returning a function which restores it.
+func privateUmask() func() {
+	old := syscall.Umask(0077)
+	return func() { syscall.Umask(old) }
+}
@@ -0,0 +1,6 @@
+package main
+
+// privateUmask does nothing on Windows, which doesn't have a umask.
+func privateUmask() func() {
+	return func() {}
+}
@@ -0,0 +1,117 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/skratchdot/open-golang/open"
+
+	"github.com/benbalter/zoom-go"
+)
+
+// durationList is a flag.Value for a comma-separated list of durations, e.g. "5m,1m".
+type durationList []time.Duration
+
+func (d *durationList) String() string {
+	parts := []string{}
+	for _, duration := range *d {
+		parts = append(parts, duration.String())
+	}
+	return strings.Join(parts, ",")
+}
+
+func (d *durationList) Set(value string) error {
+	*d = durationList{}
+	for _, part := range strings.Split(value, ",") {
+		if strings.TrimSpace(part) == "" {
+			continue
+		}
+		duration, err := time.ParseDuration(strings.TrimSpace(part))
+		if err != nil {
+			return err
+		}
+		*d = append(*d, duration)
+	}
+	return nil
+}
+
+// runWatch runs continuously, notifying about each meeting and opening it shortly before it starts.
+func runWatch(args []string) {
+	settings := mustLoadSettings()
+
+	joinBefore := zoom.DefaultJoinBefore
+	if settings.JoinBefore.Duration > 0 {
+		joinBefore = settings.JoinBefore.Duration
+	}
+	pollInterval := zoom.DefaultPollInterval
+	if settings.PollInterval.Duration > 0 {
+		pollInterval = settings.PollInterval.Duration
+	}
+	notifyBefore := durationList(zoom.DefaultNotifyBefore)
+	if settings.NotifyBefore != nil {
+		notifyBefore = durationList{}
+		for _, before := range settings.NotifyBefore {
+			notifyBefore = append(notifyBefore, before.Duration)
+		}
+	}
+	autoJoin := settings.AutoJoin == nil || *settings.AutoJoin
+
+	flags := flag.NewFlagSet("zoom watch", flag.ExitOnError)
+	fetch := newFetchOptions(settings)
+	flags.DurationVar(&joinBefore, "join-before", joinBefore, "How long before a meeting starts to open it")
+	flags.BoolVar(&autoJoin, "open", autoJoin, "Open meetings automatically")
+	flags.Var(&notifyBefore, "notify", "Comma-separated times before a meeting starts to show a notification, or empty for none")
+	quietHoursText := flags.String("quiet-hours", settings.QuietHours, "Local times not to show notifications, e.g. 22:00-07:00")
+	flags.DurationVar(&pollInterval, "poll", pollInterval, "How often to check the calendar for changes")
+	flags.BoolVar(&fetch.sync, "sync", true, "Keep a local copy of your calendar in sync, only fetching what changed since the last poll")
+	flags.Parse(args)
+
+	quietHours, err := zoom.ParseQuietHours(*quietHoursText)
+	if err != nil {
+		fmt.Printf("%v\n", err)
+		os.Exit(exitUsage)
+	}
+
+	source, err := fetch.source(fetch.filter)
+	if err != nil {
+		fatal("error creating calendar source", err)
+	}
+
+	opener := zoom.OpenerFunc(open.Run)
+	logger := log.New(os.Stderr, "", log.LstdFlags)
+	watcher := &zoom.Watcher{
+		Source:       source,
+		Logger:       logger,
+		JoinBefore:   joinBefore,
+		NotifyBefore: notifyBefore,
+		QuietHours:   quietHours,
+		PollInterval: pollInterval,
+		Filter:       fetch.filter,
+		Hooks:        settingsHooks(settings),
+	}
+	if autoJoin {
+		watcher.Opener = opener
+		logger.Printf("Opening meetings %s before they start.", joinBefore)
+	}
+	if len(notifyBefore) > 0 {
+		notifier := zoom.NewNotifySendNotifier(opener)
+		notifier.Hooks = watcher.Hooks
+		notifier.Logger = logger
+		watcher.Notifier = notifier
+		logger.Printf("Notifying %s before meetings start.", strings.Replace(notifyBefore.String(), ",", " and ", -1))
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	logger.Println("Watching your calendar. Press Ctrl-C to stop.")
+	watcher.Run(ctx)
+	logger.Println("Stopped watching.")
+}
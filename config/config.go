@@ -2,6 +2,8 @@
 package config
 
 import (
+	"encoding/json"
+	"fmt"
 	"io/ioutil"
 
 	"github.com/pkg/errors"
@@ -15,8 +17,30 @@ var (
 	ErrNoGoogleClientConfig = errors.New("missing google client config")
 	// ErrNoGoogleToken indicatges that the token is missing.
 	ErrNoGoogleToken = errors.New("missing google token")
+	// ErrMalformedConfig indicates that a configuration file could not be parsed.
+	ErrMalformedConfig = errors.New("malformed config")
 )
 
+// MalformedConfigError indicates that the configuration file at Path could not be parsed.
+type MalformedConfigError struct {
+	Path string
+	Err  error
+}
+
+func (e *MalformedConfigError) Error() string {
+	return fmt.Sprintf("malformed config %s: %v", e.Path, e.Err)
+}
+
+// Unwrap returns the underlying parse error.
+func (e *MalformedConfigError) Unwrap() error {
+	return e.Err
+}
+
+// Is makes MalformedConfigError match ErrMalformedConfig.
+func (e *MalformedConfigError) Is(target error) bool {
+	return target == ErrMalformedConfig
+}
+
 // Provider is a token provider.
 type Provider interface {
 	// GoogleClientConfig returns the Google client config.
@@ -45,10 +69,28 @@ func ReadGoogleClientConfigFromFile(filepath string) (*oauth2.Config, error) {
 		return nil, errors.WithStack(err)
 	}
 
+	if !isGoogleClientSecret(b) {
+		return nil, &MalformedConfigError{Path: filepath, Err: errors.New(`expected "installed" or "web" credentials`)}
+	}
+	return parseGoogleClientSecret(filepath, b)
+}
+
+// isGoogleClientSecret returns true if the content is a client secret downloaded from the Google Developer Console,
+// rather than an *oauth2.Config stored by a Provider.
+func isGoogleClientSecret(b []byte) bool {
+	var secret struct {
+		Installed json.RawMessage `json:"installed"`
+		Web       json.RawMessage `json:"web"`
+	}
+	return json.Unmarshal(b, &secret) == nil && (secret.Installed != nil || secret.Web != nil)
+}
+
+// parseGoogleClientSecret parses a client secret downloaded from the Google Developer Console.
+func parseGoogleClientSecret(filepath string, b []byte) (*oauth2.Config, error) {
 	// If modifying these scopes, delete your previously saved client_secret.json.
 	conf, err := google.ConfigFromJSON(b, calendar.CalendarReadonlyScope)
 	if err != nil {
-		return nil, errors.WithStack(err)
+		return nil, &MalformedConfigError{Path: filepath, Err: err}
 	}
 	return conf, nil
 }
@@ -0,0 +1,33 @@
+package config
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// Duration is a time.Duration which is stored in JSON as a string like "90m" or "168h".
+type Duration struct {
+	time.Duration
+}
+
+// MarshalJSON encodes the duration as a string.
+func (d Duration) MarshalJSON() ([]byte, error) {
+	return json.Marshal(d.String())
+}
+
+// UnmarshalJSON decodes the duration from a string like "90m".
+func (d *Duration) UnmarshalJSON(b []byte) error {
+	var s string
+	if err := json.Unmarshal(b, &s); err != nil {
+		return errors.Wrap(err, "duration must be a string like \"90m\"")
+	}
+
+	duration, err := time.ParseDuration(s)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	d.Duration = duration
+	return nil
+}
@@ -2,6 +2,7 @@ package config
 
 import (
 	"encoding/json"
+	"io/ioutil"
 	"os"
 	"os/user"
 	"path/filepath"
@@ -53,17 +54,7 @@ func (f *FileProvider) GoogleClientConfig() (*oauth2.Config, error) {
 
 	path := filepath.Join(f.directory, googleClientConfigFilename)
 
-	conf, err := ReadGoogleClientConfigFromFile(path)
-	if err != nil && err.Error() != "oauth2/google: no credentials found" {
-		return conf, err
-	}
-	if err == nil && conf != nil {
-		return conf, f.StoreGoogleClientConfig(conf)
-	}
-
-	conf = &oauth2.Config{}
-
-	fd, err := os.Open(path)
+	b, err := ioutil.ReadFile(path)
 	if err != nil {
 		if os.IsNotExist(err) {
 			return nil, ErrNoGoogleClientConfig
@@ -71,26 +62,30 @@ func (f *FileProvider) GoogleClientConfig() (*oauth2.Config, error) {
 		return nil, errors.WithStack(err)
 	}
 
+	// The file may be a client secret downloaded from Google, e.g. by zoom_launcher.
+	if isGoogleClientSecret(b) {
+		conf, err := parseGoogleClientSecret(path, b)
+		if err != nil {
+			return nil, err
+		}
+		return conf, f.StoreGoogleClientConfig(conf)
+	}
+
+	conf := &oauth2.Config{}
+	if err := json.Unmarshal(b, conf); err != nil {
+		return nil, &MalformedConfigError{Path: path, Err: err}
+	}
+
 	f.cachedGoogleClientConfig = conf
 
-	return conf, json.NewDecoder(fd).Decode(conf)
+	return conf, nil
 }
 
 // StoreGoogleClientConfig writes the Google client config to the configuration file.
 func (f *FileProvider) StoreGoogleClientConfig(conf *oauth2.Config) error {
 	f.cachedGoogleClientConfig = conf
 
-	err := f.ensureDirectoryExists()
-	if err != nil {
-		return errors.WithStack(err)
-	}
-
-	fd, err := os.Create(filepath.Join(f.directory, googleClientConfigFilename))
-	if err != nil {
-		return errors.WithStack(err)
-	}
-
-	return json.NewEncoder(fd).Encode(conf)
+	return f.writeJSON(googleClientConfigFilename, conf)
 }
 
 // GoogleTokenExists returns true if the token is readable and valid, false otherwise.
@@ -105,9 +100,9 @@ func (f *FileProvider) GoogleToken() (*oauth2.Token, error) {
 		return f.cachedGoogleToken, nil
 	}
 
-	token := &oauth2.Token{}
+	path := filepath.Join(f.directory, googleTokenFilename)
 
-	fd, err := os.Open(filepath.Join(f.directory, googleTokenFilename))
+	b, err := ioutil.ReadFile(path)
 	if err != nil {
 		if os.IsNotExist(err) {
 			return nil, ErrNoGoogleToken
@@ -115,24 +110,35 @@ func (f *FileProvider) GoogleToken() (*oauth2.Token, error) {
 		return nil, errors.WithStack(err)
 	}
 
+	token := &oauth2.Token{}
+	if err := json.Unmarshal(b, token); err != nil {
+		return nil, &MalformedConfigError{Path: path, Err: err}
+	}
+
 	f.cachedGoogleToken = token
 
-	return token, json.NewDecoder(fd).Decode(token)
+	return token, nil
 }
 
 // StoreGoogleToken writes the Google token to the configuration file.
 func (f *FileProvider) StoreGoogleToken(token *oauth2.Token) error {
 	f.cachedGoogleToken = token
 
+	return f.writeJSON(googleTokenFilename, token)
+}
+
+// writeJSON encodes the value as JSON into the named file in the configuration directory.
+func (f *FileProvider) writeJSON(filename string, v interface{}) error {
 	err := f.ensureDirectoryExists()
 	if err != nil {
 		return errors.WithStack(err)
 	}
 
-	fd, err := os.Create(filepath.Join(f.directory, googleTokenFilename))
+	fd, err := os.Create(filepath.Join(f.directory, filename))
 	if err != nil {
 		return errors.WithStack(err)
 	}
+	defer fd.Close()
 
-	return json.NewEncoder(fd).Encode(token)
+	return errors.WithStack(json.NewEncoder(fd).Encode(v))
 }
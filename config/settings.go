@@ -0,0 +1,187 @@
+package config
+
+import (
+	"encoding/json"
+	"os"
+	"os/user"
+	"path/filepath"
+
+	"github.com/pkg/errors"
+)
+
+// Settings holds the user's preferences for the zoom command.
+type Settings struct {
+	// Template is the text/template used to print each meeting.
+	Template string `json:"template,omitempty"`
+
+	// Pick is how zoom chooses between meetings starting at the same time: "ask", or a policy
+	// like "first", "most-attendees", "accepted" or "organized-by-me".
+	Pick string `json:"pick,omitempty"`
+
+	// IncludeDeclined includes meetings you declined, which are left out by default.
+	IncludeDeclined bool `json:"include_declined,omitempty"`
+
+	// IncludeCancelled includes cancelled meetings, which are left out by default.
+	IncludeCancelled bool `json:"include_cancelled,omitempty"`
+
+	// EventTypes overrides how kinds of events are handled, e.g. {"all-day": "skip", "focus-time": "list"}.
+	// Kinds are meeting, all-day, out-of-office, focus-time and working-location, and each is
+	// joined, listed without being joined automatically, or skipped.
+	EventTypes map[string]string `json:"event_types,omitempty"`
+
+	// Series limits meetings to the recurring series with these IDs or title patterns, e.g. "^Standup".
+	Series []string `json:"series,omitempty"`
+
+	// ExcludeSeries leaves out the recurring series with these IDs or title patterns.
+	ExcludeSeries []string `json:"exclude_series,omitempty"`
+
+	// Rules decide which meetings are included and opened automatically. The first rule which matches applies.
+	Rules []RuleSettings `json:"rules,omitempty"`
+
+	// TimeZone is the IANA time zone meetings are displayed in, e.g. "Europe/London". It defaults to the local one.
+	TimeZone string `json:"time_zone,omitempty"`
+
+	// TravelTime is how long it takes to get between meetings in different places. Meetings closer together
+	// than this are reported as conflicts.
+	TravelTime Duration `json:"travel_time,omitempty"`
+
+	// WorkingHours are the local times zoom report looks for free time in, e.g. "09:00-17:00".
+	WorkingHours string `json:"working_hours,omitempty"`
+
+	// Horizon is how far ahead to look for the next meetings.
+	Horizon Duration `json:"horizon,omitempty"`
+
+	// Timeout is how long to wait for Google before falling back to cached meetings.
+	Timeout Duration `json:"timeout,omitempty"`
+
+	// Sync keeps a local copy of the calendar in sync instead of listing events on every run.
+	Sync bool `json:"sync,omitempty"`
+
+	// JoinBefore is how long before a meeting starts zoom watch opens it.
+	JoinBefore Duration `json:"join_before,omitempty"`
+
+	// PollInterval is how often zoom watch checks the calendar for changes.
+	PollInterval Duration `json:"poll_interval,omitempty"`
+
+	// AutoJoin is whether zoom watch opens meetings automatically. It defaults to true.
+	AutoJoin *bool `json:"auto_join,omitempty"`
+
+	// NotifyBefore are how long before a meeting starts zoom watch shows notifications.
+	NotifyBefore []Duration `json:"notify_before,omitempty"`
+
+	// QuietHours are the local times zoom watch doesn't show notifications, e.g. "22:00-07:00".
+	QuietHours string `json:"quiet_hours,omitempty"`
+
+	// ServeAddr is the local address zoom serve listens on.
+	ServeAddr string `json:"serve_addr,omitempty"`
+
+	// ServeToken is the bearer token zoom serve requires clients to send.
+	ServeToken string `json:"serve_token,omitempty"`
+
+	// NotesPath is the Go text/template for the path of zoom notes' files, e.g. "~/Notes/{{.Date}} {{.Title | filename}}.md".
+	NotesPath string `json:"notes_path,omitempty"`
+
+	// NotesTemplate is the file containing the Go text/template zoom notes creates notes from.
+	NotesTemplate string `json:"notes_template,omitempty"`
+
+	// Hooks are commands run before joining a meeting and after it ends.
+	Hooks []HookSettings `json:"hooks,omitempty"`
+
+	// Presence configures the hooks zoom presence fires when you join or leave meetings.
+	Presence PresenceSettings `json:"presence"`
+}
+
+// PresenceSettings configures zoom presence.
+type PresenceSettings struct {
+	// Soon is how long before a meeting starts the presence becomes meeting-soon.
+	Soon Duration `json:"soon,omitempty"`
+
+	// Commands are shell commands run on each presence change.
+	Commands []string `json:"commands,omitempty"`
+
+	// Webhooks are URLs each presence change is posted to.
+	Webhooks []WebhookSettings `json:"webhooks,omitempty"`
+
+	// StateFile is the file the latest presence is written to.
+	StateFile string `json:"state_file,omitempty"`
+}
+
+// HookSettings is a command run before joining a meeting or after it ends.
+type HookSettings struct {
+	// Event is before-join or after-meeting.
+	Event string `json:"event"`
+
+	// Command is the shell command to run.
+	Command string `json:"command"`
+
+	// Timeout is how long the command may run.
+	Timeout Duration `json:"timeout,omitempty"`
+
+	// OnFailure is warn, ignore or abort, which doesn't join the meeting if a before-join hook fails.
+	OnFailure string `json:"on_failure,omitempty"`
+}
+
+// RuleSettings is a rule deciding what happens to the meetings it matches. Every condition given must match.
+type RuleSettings struct {
+	Name string `json:"name,omitempty"`
+
+	// Title is a regular expression matched against the meeting's title.
+	Title string `json:"title,omitempty"`
+
+	// Organizer is a regular expression matched against the organizer's name and email.
+	Organizer string `json:"organizer,omitempty"`
+
+	// Calendar is the ID of the calendar the meeting belongs to, e.g. a colleague's email address.
+	Calendar string `json:"calendar,omitempty"`
+
+	// MinAttendees and MaxAttendees bound the number of people invited.
+	MinAttendees int `json:"min_attendees,omitempty"`
+	MaxAttendees int `json:"max_attendees,omitempty"`
+
+	// Color is the event's color, e.g. "tomato", or color ID.
+	Color string `json:"color,omitempty"`
+
+	// Provider is the host the meeting is on, e.g. "zoom.us".
+	Provider string `json:"provider,omitempty"`
+
+	// Action is include, exclude or never-auto-join.
+	Action string `json:"action"`
+}
+
+// WebhookSettings is a webhook fired on presence changes.
+type WebhookSettings struct {
+	URL string `json:"url"`
+
+	// Secret signs each payload with HMAC-SHA256, if set.
+	Secret string `json:"secret,omitempty"`
+}
+
+// DefaultSettingsPath returns the path of the settings file in the user's configuration directory.
+func DefaultSettingsPath() (string, error) {
+	usr, err := user.Current()
+	if err != nil {
+		return "", errors.WithStack(err)
+	}
+
+	return filepath.Join(usr.HomeDir, ".config", "zoom", "config.json"), nil
+}
+
+// ReadSettingsFromFile reads the settings stored in the file.
+// If the file does not exist, it returns empty settings.
+func ReadSettingsFromFile(path string) (*Settings, error) {
+	settings := &Settings{}
+
+	fd, err := os.Open(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return settings, nil
+		}
+		return nil, errors.WithStack(err)
+	}
+	defer fd.Close()
+
+	if err := json.NewDecoder(fd).Decode(settings); err != nil {
+		return nil, &MalformedConfigError{Path: path, Err: err}
+	}
+	return settings, nil
+}
@@ -0,0 +1,127 @@
+package zoom
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// DefaultTravelTime is how long it takes to get from one place to another between meetings.
+// Meetings in different places closer together than this conflict.
+const DefaultTravelTime = 15 * time.Minute
+
+// ConflictKind is the way two meetings conflict.
+type ConflictKind string
+
+// The kinds of conflicts.
+const (
+	// ConflictOverlap is two meetings at the same time.
+	ConflictOverlap ConflictKind = "overlap"
+	// ConflictBackToBack is a meeting starting the moment another ends, leaving no break in between.
+	ConflictBackToBack ConflictKind = "back-to-back"
+	// ConflictLocation is two meetings in different places without time to get from one to the other.
+	ConflictLocation ConflictKind = "location"
+)
+
+// Conflict is a pair of meetings which conflict. First starts no later than Second.
+type Conflict struct {
+	Kind   ConflictKind `json:"kind"`
+	First  Meeting      `json:"first"`
+	Second Meeting      `json:"second"`
+	// Overlap is how long the meetings overlap. It's 0 unless Kind is ConflictOverlap.
+	Overlap time.Duration `json:"-"`
+	// OverlapMinutes is Overlap in minutes, for JSON.
+	OverlapMinutes int `json:"overlap_minutes,omitempty"`
+	// Gap is how long there is between the meetings. It's 0 unless Kind is ConflictLocation.
+	Gap time.Duration `json:"-"`
+	// GapMinutes is Gap in minutes, for JSON.
+	GapMinutes int `json:"gap_minutes,omitempty"`
+}
+
+// String describes the conflict, e.g. `"Standup" overlaps "Design Review" by 15 min`.
+func (c Conflict) String() string {
+	switch c.Kind {
+	case ConflictOverlap:
+		return fmt.Sprintf("%q overlaps %q by %s", c.First.Title, c.Second.Title, formatDuration(c.Overlap))
+	case ConflictBackToBack:
+		return fmt.Sprintf("%q ends as %q starts", c.First.Title, c.Second.Title)
+	case ConflictLocation:
+		return fmt.Sprintf("%q in %s is %s before %q in %s", c.First.Title, c.First.Place, formatDuration(c.Gap), c.Second.Title, c.Second.Place)
+	default:
+		return fmt.Sprintf("%q conflicts with %q", c.First.Title, c.Second.Title)
+	}
+}
+
+// Involves returns true if the meeting is one of the conflicting meetings.
+func (c Conflict) Involves(meeting Meeting) bool {
+	return sameMeeting(c.First, meeting) || sameMeeting(c.Second, meeting)
+}
+
+// sameMeeting returns true if both are the same meeting.
+func sameMeeting(a, b Meeting) bool {
+	if a.ID != "" || b.ID != "" {
+		return a.ID == b.ID && a.Start.Equal(b.Start)
+	}
+	return a.Title == b.Title && a.Start.Equal(b.Start)
+}
+
+// FindConflicts returns the conflicts between the meetings, in order of when they happen: meetings which overlap,
+// meetings which start the moment another ends, and meetings in different places less than travelTime apart.
+// All-day events and meetings without a start time never conflict. Meetings without an end time are assumed to
+// last 30 minutes. Only the meetings given are compared, and zoom only fetches Zoom meetings, so time blocked
+// by other events, like in-person meetings without a Zoom link, isn't checked.
+func FindConflicts(meetings []Meeting, travelTime time.Duration) []Conflict {
+	timed := []Meeting{}
+	for _, meeting := range meetings {
+		if !meeting.Start.IsZero() && !meeting.AllDay {
+			timed = append(timed, meeting)
+		}
+	}
+	sort.SliceStable(timed, func(i, j int) bool {
+		return timed[i].Start.Before(timed[j].Start)
+	})
+
+	conflicts := []Conflict{}
+	for i, first := range timed {
+		end := meetingEnd(first)
+		for _, second := range timed[i+1:] {
+			gap := second.Start.Sub(end)
+			if gap > travelTime {
+				break
+			}
+
+			conflict := Conflict{First: first, Second: second}
+			switch {
+			case gap < 0:
+				conflict.Kind = ConflictOverlap
+				conflict.Overlap = -gap
+				if secondEnd := meetingEnd(second); secondEnd.Before(end) {
+					conflict.Overlap = secondEnd.Sub(second.Start)
+				}
+				conflict.OverlapMinutes = int(conflict.Overlap.Minutes())
+			case first.Place != "" && second.Place != "" && !strings.EqualFold(first.Place, second.Place):
+				conflict.Kind = ConflictLocation
+				conflict.Gap = gap
+				conflict.GapMinutes = int(gap.Minutes())
+			case gap == 0:
+				conflict.Kind = ConflictBackToBack
+			default:
+				continue
+			}
+			conflicts = append(conflicts, conflict)
+		}
+	}
+	return conflicts
+}
+
+// ConflictsInvolving returns the conflicts which involve the meeting.
+func ConflictsInvolving(conflicts []Conflict, meeting Meeting) []Conflict {
+	involving := []Conflict{}
+	for _, conflict := range conflicts {
+		if conflict.Involves(meeting) {
+			involving = append(involving, conflict)
+		}
+	}
+	return involving
+}
@@ -0,0 +1,111 @@
+package zoom
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	calendar "google.golang.org/api/calendar/v3"
+)
+
+func TestFindConflicts(t *testing.T) {
+	nine := time.Date(2018, time.October, 10, 9, 0, 0, 0, time.UTC)
+	meetings := []Meeting{
+		NewMeeting(testEvent("standup", "standup", nine, lasting(30*time.Minute))),
+		NewMeeting(testEvent("review", "review", nine.Add(15*time.Minute), lasting(time.Hour))),
+		NewMeeting(testEvent("planning", "planning", nine.Add(75*time.Minute), lasting(30*time.Minute), withLocation("Room 101"))),
+		NewMeeting(testEvent("lunch", "lunch", nine.Add(115*time.Minute), lasting(time.Hour), withLocation("Cafe"))),
+		NewMeeting(testEvent("retro", "retro", nine.Add(5*time.Hour), lasting(time.Hour), withLocation("Room 101"))),
+		NewMeeting(testEvent("demo", "demo", nine.Add(6*time.Hour+10*time.Minute), lasting(time.Hour), withLocation("Room 101"))),
+	}
+	offsite := NewMeeting(testEvent("offsite", "offsite", nine, allDay()))
+
+	conflicts := FindConflicts(append(meetings, offsite), DefaultTravelTime)
+	require.Len(t, conflicts, 3)
+
+	assert.Equal(t, ConflictOverlap, conflicts[0].Kind)
+	assert.Equal(t, 15*time.Minute, conflicts[0].Overlap)
+	assert.Equal(t, `"standup" overlaps "review" by 15 min`, conflicts[0].String())
+
+	assert.Equal(t, ConflictBackToBack, conflicts[1].Kind)
+	assert.Equal(t, `"review" ends as "planning" starts`, conflicts[1].String())
+
+	assert.Equal(t, ConflictLocation, conflicts[2].Kind)
+	assert.Equal(t, `"planning" in Room 101 is 10 min before "lunch" in Cafe`, conflicts[2].String())
+
+	assert.Len(t, ConflictsInvolving(conflicts, meetings[1]), 2)
+	assert.Empty(t, ConflictsInvolving(conflicts, meetings[4]))
+}
+
+func TestFindConflicts_Contained(t *testing.T) {
+	nine := time.Date(2018, time.October, 10, 9, 0, 0, 0, time.UTC)
+	conflicts := FindConflicts([]Meeting{
+		NewMeeting(testEvent("workshop", "workshop", nine, lasting(3*time.Hour))),
+		NewMeeting(testEvent("standup", "standup", nine.Add(time.Hour), lasting(15*time.Minute))),
+	}, DefaultTravelTime)
+
+	require.Len(t, conflicts, 1)
+	assert.Equal(t, 15*time.Minute, conflicts[0].Overlap)
+}
+
+func TestMeetingPlace(t *testing.T) {
+	testCases := []struct {
+		event    *calendar.Event
+		expected string
+	}{
+		{&calendar.Event{Location: "https://jithub.zoom.us/j/1"}, ""},
+		{&calendar.Event{Location: "Room 101 / https://jithub.zoom.us/j/1"}, "Room 101"},
+		{&calendar.Event{Location: "Zoom Meeting; Cafe"}, "Cafe"},
+		{&calendar.Event{
+			Location: "Building 4",
+			Attendees: []*calendar.EventAttendee{
+				{Email: "room@resource.calendar.google.com", DisplayName: "Room 101", Resource: true},
+				{Email: "lobby@resource.calendar.google.com", DisplayName: "Lobby", Resource: true, ResponseStatus: "declined"},
+			},
+		}, "Room 101"},
+	}
+	for _, testCase := range testCases {
+		assert.Equal(t, testCase.expected, meetingPlace(testCase.event), testCase.event.Location)
+	}
+}
+
+func TestWriteJSON(t *testing.T) {
+	nine := time.Date(2018, time.October, 10, 9, 0, 0, 0, time.UTC)
+	meetings := []Meeting{
+		NewMeeting(testEvent("standup", "standup", nine, lasting(30*time.Minute))),
+		NewMeeting(testEvent("review", "review", nine.Add(15*time.Minute), lasting(time.Hour))),
+	}
+
+	var output bytes.Buffer
+	require.NoError(t, Export(&output, "json", meetings, DefaultTravelTime))
+
+	var decoded struct {
+		Meetings []struct {
+			Title string `json:"title"`
+		} `json:"meetings"`
+		Conflicts []struct {
+			Kind           string `json:"kind"`
+			OverlapMinutes int    `json:"overlap_minutes"`
+		} `json:"conflicts"`
+	}
+	require.NoError(t, json.Unmarshal(output.Bytes(), &decoded))
+	assert.Len(t, decoded.Meetings, 2)
+	require.Len(t, decoded.Conflicts, 1)
+	assert.Equal(t, "overlap", decoded.Conflicts[0].Kind)
+	assert.Equal(t, 15, decoded.Conflicts[0].OverlapMinutes)
+
+	// Meetings in different places 20 minutes apart only conflict with a longer travel time.
+	apart := []Meeting{
+		NewMeeting(testEvent("planning", "planning", nine, lasting(30*time.Minute), withLocation("Room 101"))),
+		NewMeeting(testEvent("lunch", "lunch", nine.Add(50*time.Minute), lasting(time.Hour), withLocation("Cafe"))),
+	}
+	for travelTime, expected := range map[time.Duration]int{DefaultTravelTime: 0, 30 * time.Minute: 1} {
+		output.Reset()
+		require.NoError(t, WriteJSON(&output, apart, travelTime))
+		require.NoError(t, json.Unmarshal(output.Bytes(), &decoded))
+		assert.Len(t, decoded.Conflicts, expected, "travel time %s", travelTime)
+	}
+}
@@ -0,0 +1,233 @@
+package zoom
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// dateLayouts are the absolute formats accepted by ParseDate, along with whether they only specify a day.
+var dateLayouts = []struct {
+	layout   string
+	dateOnly bool
+}{
+	{time.RFC3339, false},
+	{"2006-01-02T15:04", false},
+	{"2006-01-02 15:04", false},
+	{"2006-01-02", true},
+	{"Jan 2 2006", true},
+	{"Jan 2", true},
+	{"January 2", true},
+}
+
+// ParseDate parses a natural-ish date relative to now in now's time zone. It accepts:
+//   - "now", "today", "tomorrow" and "yesterday"
+//   - weekday names like "friday", meaning the next such day (or today)
+//   - offsets like "+3d", "-2w" or "+90m"
+//   - times of day like "15:04", meaning today
+//   - absolute dates like "2006-01-02", "2006-01-02 15:04", "Jan 2" and RFC 3339
+//
+// dateOnly reports whether the input named a whole day rather than an instant,
+// in which case t is the start of that day.
+func ParseDate(input string, now time.Time) (t time.Time, dateOnly bool, err error) {
+	input = strings.TrimSpace(input)
+	keyword := strings.ToLower(input)
+	today := StartOfDay(now)
+
+	switch keyword {
+	case "now":
+		return now, false, nil
+	case "today":
+		return today, true, nil
+	case "tomorrow":
+		return today.AddDate(0, 0, 1), true, nil
+	case "yesterday":
+		return today.AddDate(0, 0, -1), true, nil
+	}
+
+	for weekday := time.Sunday; weekday <= time.Saturday; weekday++ {
+		name := strings.ToLower(weekday.String())
+		if keyword == name || keyword == name[:3] {
+			days := (int(weekday) - int(now.Weekday()) + 7) % 7
+			return today.AddDate(0, 0, days), true, nil
+		}
+	}
+
+	if offset, ok := parseOffset(input); ok {
+		return offset(now), false, nil
+	}
+
+	if clock, err := time.ParseInLocation("15:04", input, now.Location()); err == nil {
+		return time.Date(now.Year(), now.Month(), now.Day(), clock.Hour(), clock.Minute(), 0, 0, now.Location()), false, nil
+	}
+
+	for _, format := range dateLayouts {
+		parsed, err := time.ParseInLocation(format.layout, input, now.Location())
+		if err != nil {
+			continue
+		}
+		if parsed.Year() == 0 {
+			parsed = parsed.AddDate(now.Year(), 0, 0)
+		}
+		return parsed, format.dateOnly, nil
+	}
+
+	return time.Time{}, false, errors.Errorf("unable to parse date %q", input)
+}
+
+// parseOffset parses offsets like "+3d", "-2w" or "+90m" into a function which applies them.
+func parseOffset(input string) (func(time.Time) time.Time, bool) {
+	if len(input) < 3 || (input[0] != '+' && input[0] != '-') {
+		return nil, false
+	}
+
+	amount, err := strconv.Atoi(input[1 : len(input)-1])
+	if err != nil {
+		return nil, false
+	}
+	if input[0] == '-' {
+		amount = -amount
+	}
+
+	switch input[len(input)-1] {
+	case 'm':
+		return func(t time.Time) time.Time { return t.Add(time.Duration(amount) * time.Minute) }, true
+	case 'h':
+		return func(t time.Time) time.Time { return t.Add(time.Duration(amount) * time.Hour) }, true
+	case 'd':
+		return func(t time.Time) time.Time { return t.AddDate(0, 0, amount) }, true
+	case 'w':
+		return func(t time.Time) time.Time { return t.AddDate(0, 0, 7*amount) }, true
+	}
+	return nil, false
+}
+
+// StartOfDay returns midnight at the start of t's day in t's time zone.
+func StartOfDay(t time.Time) time.Time {
+	year, month, day := t.Date()
+	return time.Date(year, month, day, 0, 0, 0, 0, t.Location())
+}
+
+// DayRange returns the start and end of t's day in t's time zone.
+func DayRange(t time.Time) (time.Time, time.Time) {
+	start := StartOfDay(t)
+	return start, start.AddDate(0, 0, 1)
+}
+
+// WeekRange returns the start and end of t's week, Monday through Sunday, in t's time zone.
+func WeekRange(t time.Time) (time.Time, time.Time) {
+	daysSinceMonday := (int(t.Weekday()) + 6) % 7
+	start := StartOfDay(t).AddDate(0, 0, -daysSinceMonday)
+	return start, start.AddDate(0, 0, 7)
+}
+
+// Day is a group of meetings which start on the same day.
+type Day struct {
+	// Date is the start of the day, or the zero time for meetings without a start time.
+	Date     time.Time
+	Meetings []Meeting
+}
+
+// GroupByDay groups consecutive meetings by the day they start in the given time zone.
+// All-day events belong to their own date, whatever the time zone.
+func GroupByDay(meetings []Meeting, loc *time.Location) []Day {
+	days := []Day{}
+	for _, meeting := range meetings {
+		date := time.Time{}
+		if meeting.AllDay {
+			year, month, day := meeting.Start.Date()
+			date = time.Date(year, month, day, 0, 0, 0, 0, loc)
+		} else if !meeting.Start.IsZero() {
+			date = StartOfDay(meeting.Start.In(loc))
+		}
+
+		if len(days) == 0 || !days[len(days)-1].Date.Equal(date) {
+			days = append(days, Day{Date: date})
+		}
+		days[len(days)-1].Meetings = append(days[len(days)-1].Meetings, meeting)
+	}
+	return days
+}
+
+// parseClockRange parses a range of times of day like "22:00-07:00" into offsets from midnight.
+func parseClockRange(s string) (start, end time.Duration, ok bool) {
+	parts := strings.Split(s, "-")
+	if len(parts) != 2 {
+		return 0, 0, false
+	}
+
+	var bounds [2]time.Duration
+	for i, part := range parts {
+		clock, err := time.Parse("15:04", strings.TrimSpace(part))
+		if err != nil {
+			return 0, 0, false
+		}
+		bounds[i] = time.Duration(clock.Hour())*time.Hour + time.Duration(clock.Minute())*time.Minute
+	}
+	return bounds[0], bounds[1], true
+}
+
+// formatClockRange formats offsets from midnight as a range of times of day like "22:00-07:00".
+func formatClockRange(start, end time.Duration) string {
+	format := func(d time.Duration) string {
+		return fmt.Sprintf("%02d:%02d", int(d/time.Hour), int(d%time.Hour/time.Minute))
+	}
+	return format(start) + "-" + format(end)
+}
+
+// LoadTimeZone returns the time zone with the IANA name, e.g. "Europe/London".
+// An empty name or "local" is the local time zone.
+func LoadTimeZone(name string) (*time.Location, error) {
+	if name == "" || strings.EqualFold(name, "local") {
+		return time.Local, nil
+	}
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, errors.Errorf("unknown time zone %q, expected e.g. America/New_York", name)
+	}
+	return loc, nil
+}
+
+// formatTimeRange formats the start and end in the time zone, e.g. "Mon Oct 8 09:30–10:00 PDT".
+// The end is left out if it's zero, the day is repeated if the range spans midnight, and each side gets its own
+// abbreviation if daylight saving time starts or ends in between.
+func formatTimeRange(start, end time.Time, loc *time.Location) string {
+	start = start.In(loc)
+	if end.IsZero() || !end.After(start) {
+		return start.Format("Mon Jan 2 15:04 MST")
+	}
+	end = end.In(loc)
+
+	sameDay := start.YearDay() == end.YearDay() && start.Year() == end.Year()
+	sameZone := start.Format("MST") == end.Format("MST")
+	switch {
+	case sameDay && sameZone:
+		return start.Format("Mon Jan 2 15:04") + "–" + end.Format("15:04 MST")
+	case sameDay:
+		return start.Format("Mon Jan 2 15:04 MST") + "–" + end.Format("15:04 MST")
+	case sameZone:
+		return start.Format("Mon Jan 2 15:04") + " – " + end.Format("Mon Jan 2 15:04 MST")
+	default:
+		return start.Format("Mon Jan 2 15:04 MST") + " – " + end.Format("Mon Jan 2 15:04 MST")
+	}
+}
+
+// formatDateRange formats the days an all-day event lasts, e.g. "Mon Oct 8" or "Mon Oct 8 – Wed Oct 10".
+// The end is exclusive, as in the Calendar API.
+func formatDateRange(start, end time.Time) string {
+	last := end.AddDate(0, 0, -1)
+	if end.IsZero() || !last.After(start) {
+		return start.Format("Mon Jan 2")
+	}
+	return start.Format("Mon Jan 2") + " – " + last.Format("Mon Jan 2")
+}
+
+// sameClock returns true if clocks in both time zones read the same at t.
+func sameClock(t time.Time, a, b *time.Location) bool {
+	_, offsetA := t.In(a).Zone()
+	_, offsetB := t.In(b).Zone()
+	return offsetA == offsetB
+}
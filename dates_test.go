@@ -0,0 +1,150 @@
+package zoom
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	calendar "google.golang.org/api/calendar/v3"
+)
+
+func TestParseDate(t *testing.T) {
+	newYork, err := time.LoadLocation("America/New_York")
+	require.NoError(t, err)
+
+	// A Wednesday afternoon.
+	now := time.Date(2018, time.October, 10, 14, 30, 0, 0, newYork)
+
+	testCases := []struct {
+		input    string
+		expected time.Time
+		dateOnly bool
+	}{
+		{"now", now, false},
+		{"Today", time.Date(2018, time.October, 10, 0, 0, 0, 0, newYork), true},
+		{"tomorrow", time.Date(2018, time.October, 11, 0, 0, 0, 0, newYork), true},
+		{"yesterday", time.Date(2018, time.October, 9, 0, 0, 0, 0, newYork), true},
+		{"wednesday", time.Date(2018, time.October, 10, 0, 0, 0, 0, newYork), true},
+		{"fri", time.Date(2018, time.October, 12, 0, 0, 0, 0, newYork), true},
+		{"monday", time.Date(2018, time.October, 15, 0, 0, 0, 0, newYork), true},
+		{"+2h", time.Date(2018, time.October, 10, 16, 30, 0, 0, newYork), false},
+		{"-90m", time.Date(2018, time.October, 10, 13, 0, 0, 0, newYork), false},
+		{"+3d", time.Date(2018, time.October, 13, 14, 30, 0, 0, newYork), false},
+		{"+1w", time.Date(2018, time.October, 17, 14, 30, 0, 0, newYork), false},
+		{"09:15", time.Date(2018, time.October, 10, 9, 15, 0, 0, newYork), false},
+		{"2018-11-05", time.Date(2018, time.November, 5, 0, 0, 0, 0, newYork), true},
+		{"2018-11-05 16:00", time.Date(2018, time.November, 5, 16, 0, 0, 0, newYork), false},
+		{"Nov 5", time.Date(2018, time.November, 5, 0, 0, 0, 0, newYork), true},
+		{"2018-11-05T16:00:00Z", time.Date(2018, time.November, 5, 16, 0, 0, 0, time.UTC), false},
+	}
+	for _, testCase := range testCases {
+		actual, dateOnly, err := ParseDate(testCase.input, now)
+		require.NoError(t, err, "input: %q", testCase.input)
+		assert.True(t, testCase.expected.Equal(actual), "input: %q, expected %s, got %s", testCase.input, testCase.expected, actual)
+		assert.Equal(t, testCase.dateOnly, dateOnly, "input: %q", testCase.input)
+	}
+
+	_, _, err = ParseDate("next blue moon", now)
+	assert.EqualError(t, err, `unable to parse date "next blue moon"`)
+}
+
+func TestWeekRange(t *testing.T) {
+	start, end := WeekRange(time.Date(2018, time.October, 14, 18, 0, 0, 0, time.UTC))
+	assert.Equal(t, time.Date(2018, time.October, 8, 0, 0, 0, 0, time.UTC), start)
+	assert.Equal(t, time.Date(2018, time.October, 15, 0, 0, 0, 0, time.UTC), end)
+}
+
+func TestDayRange_DaylightSavingTime(t *testing.T) {
+	newYork, err := time.LoadLocation("America/New_York")
+	require.NoError(t, err)
+
+	start, end := DayRange(time.Date(2018, time.November, 4, 12, 0, 0, 0, newYork))
+	assert.Equal(t, 25*time.Hour, end.Sub(start))
+}
+
+func TestGroupByDay(t *testing.T) {
+	meetings := []Meeting{
+		{Title: "a", Start: time.Date(2018, time.October, 8, 9, 0, 0, 0, time.UTC)},
+		{Title: "b", Start: time.Date(2018, time.October, 8, 23, 0, 0, 0, time.UTC)},
+		{Title: "c", Start: time.Date(2018, time.October, 9, 1, 0, 0, 0, time.UTC)},
+	}
+
+	days := GroupByDay(meetings, time.UTC)
+	require.Len(t, days, 2)
+	assert.Equal(t, time.Date(2018, time.October, 8, 0, 0, 0, 0, time.UTC), days[0].Date)
+	assert.Len(t, days[0].Meetings, 2)
+	assert.Len(t, days[1].Meetings, 1)
+
+	// In Los Angeles, all three meetings are on the 8th.
+	losAngeles, err := time.LoadLocation("America/Los_Angeles")
+	require.NoError(t, err)
+	days = GroupByDay(meetings, losAngeles)
+	require.Len(t, days, 1)
+	assert.Len(t, days[0].Meetings, 3)
+}
+
+func TestMeeting_Times(t *testing.T) {
+	newYork, err := time.LoadLocation("America/New_York")
+	require.NoError(t, err)
+	london, err := time.LoadLocation("Europe/London")
+	require.NoError(t, err)
+	kolkata, err := time.LoadLocation("Asia/Kolkata")
+	require.NoError(t, err)
+
+	meeting := func(start, end time.Time, timeZone string) Meeting {
+		return NewMeeting(&calendar.Event{
+			Start: &calendar.EventDateTime{DateTime: start.Format(time.RFC3339), TimeZone: timeZone},
+			End:   &calendar.EventDateTime{DateTime: end.Format(time.RFC3339), TimeZone: timeZone},
+		})
+	}
+	standup := time.Date(2018, time.October, 10, 9, 30, 0, 0, newYork)
+
+	testCases := []struct {
+		name     string
+		meeting  Meeting
+		loc      *time.Location
+		times    string
+		original string
+	}{
+		{"local", meeting(standup, standup.Add(30*time.Minute), "America/New_York"), newYork, "Wed Oct 10 09:30–10:00 EDT", ""},
+		{"elsewhere", meeting(standup, standup.Add(30*time.Minute), "America/New_York"), london, "Wed Oct 10 14:30–15:00 BST", "Wed Oct 10 09:30–10:00 EDT in America/New_York"},
+		{"half hour offset", meeting(standup, standup.Add(30*time.Minute), "Europe/London"), kolkata, "Wed Oct 10 19:00–19:30 IST", "Wed Oct 10 14:30–15:00 BST in Europe/London"},
+		{"no time zone", meeting(standup, standup.Add(30*time.Minute), ""), london, "Wed Oct 10 14:30–15:00 BST", ""},
+		{"unknown time zone", meeting(standup, standup.Add(30*time.Minute), "Mars/Olympus_Mons"), london, "Wed Oct 10 14:30–15:00 BST", ""},
+		{"no end", NewMeeting(&calendar.Event{Start: &calendar.EventDateTime{DateTime: standup.Format(time.RFC3339)}}), newYork, "Wed Oct 10 09:30 EDT", ""},
+		{"past midnight", meeting(standup.Add(14*time.Hour), standup.Add(16*time.Hour), ""), newYork, "Wed Oct 10 23:30 – Thu Oct 11 01:30 EDT", ""},
+		// Daylight saving time ends at 02:00 EDT on November 4, 2018, turning the clocks back to 01:00 EST.
+		{
+			"daylight saving time ends",
+			meeting(time.Date(2018, time.November, 4, 1, 30, 0, 0, newYork), time.Date(2018, time.November, 4, 1, 30, 0, 0, newYork).Add(time.Hour), ""),
+			newYork, "Sun Nov 4 01:30 EDT–01:30 EST", "",
+		},
+		// The UK's clocks change a week before the US's, so the offset between them changes too.
+		{
+			"daylight saving time differs",
+			meeting(time.Date(2018, time.October, 29, 9, 0, 0, 0, newYork), time.Date(2018, time.October, 29, 10, 0, 0, 0, newYork), "America/New_York"),
+			london, "Mon Oct 29 13:00–14:00 GMT", "Mon Oct 29 09:00–10:00 EDT in America/New_York",
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			meeting := testCase.meeting.In(testCase.loc)
+			assert.Equal(t, testCase.times, meeting.Times())
+			assert.Equal(t, testCase.original, meeting.OriginalTimes())
+		})
+	}
+}
+
+func TestLoadTimeZone(t *testing.T) {
+	loc, err := LoadTimeZone("")
+	require.NoError(t, err)
+	assert.Equal(t, time.Local, loc)
+
+	loc, err = LoadTimeZone("Europe/London")
+	require.NoError(t, err)
+	assert.Equal(t, "Europe/London", loc.String())
+
+	_, err = LoadTimeZone("Mars/Olympus_Mons")
+	assert.Error(t, err)
+}
@@ -0,0 +1,138 @@
+package zoom
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/benbalter/zoom-go/config"
+	"github.com/pkg/errors"
+	"golang.org/x/oauth2"
+	"google.golang.org/api/googleapi"
+)
+
+var (
+	// ErrNoMeetings indicates that there are no upcoming Zoom meetings.
+	ErrNoMeetings = errors.New("no zoom events upcoming")
+	// ErrUnauthorized indicates that the Google token is missing, invalid or has been revoked.
+	ErrUnauthorized = errors.New("unauthorized")
+	// ErrQuotaExceeded indicates that the Google Calendar API rate limit or quota has been exceeded.
+	ErrQuotaExceeded = errors.New("quota exceeded")
+	// ErrNetwork indicates that the Google Calendar API could not be reached.
+	ErrNetwork = errors.New("network error")
+	// ErrMalformedConfig indicates that a configuration file could not be parsed.
+	ErrMalformedConfig = config.ErrMalformedConfig
+)
+
+// quotaReasons are the Google API error reasons which indicate a rate limit or quota was exceeded.
+var quotaReasons = map[string]bool{
+	"rateLimitExceeded":     true,
+	"userRateLimitExceeded": true,
+	"quotaExceeded":         true,
+	"dailyLimitExceeded":    true,
+}
+
+// unauthorizedReasons are the Google API error reasons of 403 responses which mean the token can't read the
+// calendar, so a new one might. Other 403s, like the Calendar API not being enabled, aren't fixed by a new token.
+var unauthorizedReasons = map[string]bool{
+	"forbidden":               true,
+	"insufficientPermissions": true,
+}
+
+// NoMeetingsError indicates that the calendar was read successfully,
+// but no Zoom meetings were found within the horizon.
+type NoMeetingsError struct {
+	Horizon time.Duration
+}
+
+func (e *NoMeetingsError) Error() string {
+	return fmt.Sprintf("no zoom events upcoming in the next %s", humanizeDuration(e.Horizon))
+}
+
+// Is makes NoMeetingsError match ErrNoMeetings.
+func (e *NoMeetingsError) Is(target error) bool {
+	return target == ErrNoMeetings
+}
+
+// APIError is an error talking to the Google Calendar API which falls into one of the
+// categories ErrUnauthorized, ErrQuotaExceeded or ErrNetwork.
+type APIError struct {
+	// Kind is the category of the error, which the error matches with errors.Is.
+	Kind error
+	// Code is the HTTP status code of the response, if there was one.
+	Code int
+	// Reason is the Google API error reason, e.g. "rateLimitExceeded", if there was one.
+	Reason string
+	// Err is the underlying error.
+	Err error
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("%v: %v", e.Kind, e.Err)
+}
+
+// Unwrap returns the underlying error.
+func (e *APIError) Unwrap() error {
+	return e.Err
+}
+
+// Is makes APIError match its Kind.
+func (e *APIError) Is(target error) bool {
+	return target == e.Kind
+}
+
+// classifyError maps errors from the Google API client into an *APIError where possible.
+// Other errors are returned with a stack trace.
+func classifyError(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	var apiErr *googleapi.Error
+	if errors.As(err, &apiErr) {
+		reason := ""
+		if len(apiErr.Errors) > 0 {
+			reason = apiErr.Errors[0].Reason
+		}
+
+		switch {
+		case apiErr.Code == http.StatusTooManyRequests || quotaReasons[reason]:
+			return &APIError{Kind: ErrQuotaExceeded, Code: apiErr.Code, Reason: reason, Err: err}
+		case apiErr.Code == http.StatusUnauthorized || apiErr.Code == http.StatusForbidden && unauthorizedReasons[reason]:
+			return &APIError{Kind: ErrUnauthorized, Code: apiErr.Code, Reason: reason, Err: err}
+		}
+		return errors.WithStack(err)
+	}
+
+	// The token endpoint rejects expired or revoked refresh tokens.
+	var retrieveErr *oauth2.RetrieveError
+	if errors.As(err, &retrieveErr) {
+		code := 0
+		if retrieveErr.Response != nil {
+			code = retrieveErr.Response.StatusCode
+		}
+		return &APIError{Kind: ErrUnauthorized, Code: code, Reason: retrieveErr.ErrorCode, Err: err}
+	}
+
+	var netErr net.Error
+	if errors.As(err, &netErr) || errors.Is(err, context.DeadlineExceeded) {
+		return &APIError{Kind: ErrNetwork, Err: err}
+	}
+
+	return errors.WithStack(err)
+}
+
+// humanizeDuration formats whole days as days and anything else as a time.Duration.
+func humanizeDuration(d time.Duration) string {
+	const day = 24 * time.Hour
+	switch {
+	case d == day:
+		return "day"
+	case d > 0 && d%day == 0:
+		return fmt.Sprintf("%d days", d/day)
+	default:
+		return d.String()
+	}
+}
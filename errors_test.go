@@ -0,0 +1,112 @@
+package zoom
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNextEvents_ClassifiesErrors(t *testing.T) {
+	testCases := []struct {
+		status   int
+		body     string
+		expected error
+		reason   string
+	}{
+		{
+			http.StatusUnauthorized,
+			`{"error": {"code": 401, "message": "Invalid Credentials", "errors": [{"reason": "authError"}]}}`,
+			ErrUnauthorized, "authError",
+		},
+		{
+			http.StatusForbidden,
+			`{"error": {"code": 403, "message": "Rate Limit Exceeded", "errors": [{"reason": "rateLimitExceeded"}]}}`,
+			ErrQuotaExceeded, "rateLimitExceeded",
+		},
+		{
+			http.StatusForbidden,
+			`{"error": {"code": 403, "message": "Insufficient Permission", "errors": [{"reason": "insufficientPermissions"}]}}`,
+			ErrUnauthorized, "insufficientPermissions",
+		},
+		{
+			http.StatusForbidden,
+			`{"error": {"code": 403, "message": "User Rate Limit Exceeded", "errors": [{"reason": "userRateLimitExceeded"}]}}`,
+			ErrQuotaExceeded, "userRateLimitExceeded",
+		},
+		{
+			http.StatusTooManyRequests,
+			`{"error": {"code": 429, "message": "Too Many Requests"}}`,
+			ErrQuotaExceeded, "",
+		},
+	}
+
+	for _, testCase := range testCases {
+		mux := http.NewServeMux()
+		service, shutdown := newFakeGoogleCalendarService(t, mux)
+
+		mux.HandleFunc("/calendars/primary/events", func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(testCase.status)
+			fmt.Fprint(w, testCase.body)
+		})
+
+		_, err := NextEvents(service, 1)
+		shutdown()
+
+		require.Error(t, err)
+		assert.True(t, errors.Is(err, testCase.expected), "status %d: %v", testCase.status, err)
+		assert.False(t, errors.Is(err, ErrNoMeetings))
+
+		var apiErr *APIError
+		require.True(t, errors.As(err, &apiErr))
+		assert.Equal(t, testCase.status, apiErr.Code)
+		assert.Equal(t, testCase.reason, apiErr.Reason)
+	}
+}
+
+func TestNextEvents_ClassifiesNetworkErrors(t *testing.T) {
+	service, shutdown := newFakeGoogleCalendarService(t, http.NewServeMux())
+	shutdown()
+
+	_, err := NextEvents(service, 1)
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, ErrNetwork), "%v", err)
+}
+
+func TestClassifyError_ServerError(t *testing.T) {
+	mux := http.NewServeMux()
+	service, shutdown := newFakeGoogleCalendarService(t, mux)
+	defer shutdown()
+
+	mux.HandleFunc("/calendars/primary/events", func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, `{"error": {"code": 500, "message": "Backend Error"}}`, http.StatusInternalServerError)
+	})
+
+	_, err := NextEvents(service, 1)
+	require.Error(t, err)
+	for _, kind := range []error{ErrUnauthorized, ErrQuotaExceeded, ErrNetwork, ErrNoMeetings} {
+		assert.False(t, errors.Is(err, kind), "unexpectedly matched %v", kind)
+	}
+}
+
+func TestClassifyError_AccessNotConfigured(t *testing.T) {
+	mux := http.NewServeMux()
+	service, shutdown := newFakeGoogleCalendarService(t, mux)
+	defer shutdown()
+
+	mux.HandleFunc("/calendars/primary/events", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusForbidden)
+		fmt.Fprint(w, `{"error": {"code": 403, "message": "Calendar API has not been used in project 1", "errors": [{"reason": "accessNotConfigured"}]}}`)
+	})
+
+	// A new token won't enable the Calendar API, so the user isn't told to delete theirs.
+	_, err := NextEvents(service, 1)
+	require.Error(t, err)
+	assert.False(t, errors.Is(err, ErrUnauthorized), "%v", err)
+	assert.False(t, errors.Is(err, ErrQuotaExceeded), "%v", err)
+}
@@ -0,0 +1,81 @@
+package zoom
+
+import (
+	"time"
+
+	calendar "google.golang.org/api/calendar/v3"
+)
+
+// testEventOption changes an event built by testEvent.
+type testEventOption func(*calendar.Event)
+
+// testEvent returns a Zoom meeting called id, joined with the conference number, starting at start.
+func testEvent(id, conference string, start time.Time, options ...testEventOption) *calendar.Event {
+	event := &calendar.Event{
+		Id:       id,
+		Summary:  id,
+		Location: "https://jithub.zoom.us/j/" + conference,
+		Start:    &calendar.EventDateTime{DateTime: start.Format(googleCalendarDateTimeFormat)},
+	}
+	for _, option := range options {
+		option(event)
+	}
+	return event
+}
+
+// testEventStart returns when the event built by testEvent starts.
+func testEventStart(event *calendar.Event) time.Time {
+	start, err := time.Parse(googleCalendarDateTimeFormat, event.Start.DateTime)
+	if err != nil {
+		panic(err)
+	}
+	return start
+}
+
+// withEnd sets when the event ends.
+func withEnd(end time.Time) testEventOption {
+	return func(event *calendar.Event) {
+		event.End = &calendar.EventDateTime{DateTime: end.Format(googleCalendarDateTimeFormat)}
+	}
+}
+
+// lasting sets the event to end after the duration.
+func lasting(d time.Duration) testEventOption {
+	return func(event *calendar.Event) {
+		withEnd(testEventStart(event).Add(d))(event)
+	}
+}
+
+// allDay turns the event into an all-day event on the day it starts.
+func allDay() testEventOption {
+	return func(event *calendar.Event) {
+		day := testEventStart(event)
+		event.Start = &calendar.EventDateTime{Date: day.Format(googleCalendarDateFormat)}
+		event.End = &calendar.EventDateTime{Date: day.AddDate(0, 0, 1).Format(googleCalendarDateFormat)}
+	}
+}
+
+// withResponse sets your RSVP to the event, alongside the organizer's.
+func withResponse(response string) testEventOption {
+	return func(event *calendar.Event) {
+		event.Attendees = []*calendar.EventAttendee{
+			{Email: "kevin@jithub.com", ResponseStatus: "accepted"},
+			{Email: "me@jithub.com", Self: true, ResponseStatus: response},
+		}
+	}
+}
+
+// withLocation puts a room before the Zoom link in the event's location.
+func withLocation(location string) testEventOption {
+	return func(event *calendar.Event) {
+		event.Location = location + ", " + event.Location
+	}
+}
+
+// inSeries makes the event an instance of the recurring series, with an instance ID like Google's.
+func inSeries(seriesID string) testEventOption {
+	return func(event *calendar.Event) {
+		event.Id = seriesID + "_" + testEventStart(event).UTC().Format("20060102T150405Z")
+		event.RecurringEventId = seriesID
+	}
+}
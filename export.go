@@ -0,0 +1,242 @@
+package zoom
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	icsDateTimeFormat = "20060102T150405Z"
+	icsDateFormat     = "20060102"
+	icsMaxLineLength  = 75
+)
+
+// ExportFormats lists the formats supported by Export.
+var ExportFormats = []string{"ics", "csv", "markdown", "json"}
+
+// Export writes the meetings to w in the given format, one of ExportFormats.
+// The JSON format includes the conflicts between the meetings, using the travel time like FindConflicts.
+func Export(w io.Writer, format string, meetings []Meeting, travelTime time.Duration) error {
+	switch format {
+	case "ics":
+		return WriteICS(w, meetings)
+	case "csv":
+		return WriteCSV(w, meetings)
+	case "markdown", "md":
+		return WriteMarkdown(w, meetings)
+	case "json":
+		return WriteJSON(w, meetings, travelTime)
+	default:
+		return errors.Errorf("unknown export format %q, expected one of: %s", format, strings.Join(ExportFormats, ", "))
+	}
+}
+
+// WriteICS writes the meetings as an iCalendar (RFC 5545) file with the Zoom URL in URL and LOCATION.
+func WriteICS(w io.Writer, meetings []Meeting) error {
+	output := bufio.NewWriter(w)
+	now := time.Now().UTC().Format(icsDateTimeFormat)
+
+	writeICSLine(output, "BEGIN:VCALENDAR")
+	writeICSLine(output, "VERSION:2.0")
+	writeICSLine(output, "PRODID:-//benbalter//zoom-go//EN")
+	writeICSLine(output, "CALSCALE:GREGORIAN")
+	for _, meeting := range meetings {
+		writeICSLine(output, "BEGIN:VEVENT")
+		writeICSLine(output, "UID:"+icsUID(meeting))
+		writeICSLine(output, "DTSTAMP:"+now)
+		switch {
+		case meeting.AllDay:
+			writeICSLine(output, "DTSTART;VALUE=DATE:"+meeting.Start.Format(icsDateFormat))
+			if !meeting.End.IsZero() {
+				writeICSLine(output, "DTEND;VALUE=DATE:"+meeting.End.Format(icsDateFormat))
+			}
+		default:
+			if !meeting.Start.IsZero() {
+				writeICSLine(output, "DTSTART:"+meeting.Start.UTC().Format(icsDateTimeFormat))
+			}
+			if !meeting.End.IsZero() {
+				writeICSLine(output, "DTEND:"+meeting.End.UTC().Format(icsDateTimeFormat))
+			}
+		}
+		writeICSLine(output, "SUMMARY:"+escapeICSText(meeting.Title))
+		if meeting.WebURL != "" {
+			writeICSLine(output, "LOCATION:"+escapeICSText(meeting.WebURL))
+			writeICSLine(output, "URL:"+meeting.WebURL)
+		}
+		if meeting.Organizer != "" {
+			writeICSLine(output, "ORGANIZER;CN="+quoteICSParam(meeting.Organizer)+":"+icsOrganizerAddress(meeting))
+		}
+		if meeting.Status == eventStatusCancelled {
+			writeICSLine(output, "STATUS:CANCELLED")
+		}
+		if meeting.CalendarURL != "" {
+			writeICSLine(output, "DESCRIPTION:"+escapeICSText("Calendar event: "+meeting.CalendarURL))
+		}
+		writeICSLine(output, "END:VEVENT")
+	}
+	writeICSLine(output, "END:VCALENDAR")
+
+	return errors.WithStack(output.Flush())
+}
+
+// icsUID returns a globally unique identifier for the meeting.
+func icsUID(meeting Meeting) string {
+	if meeting.Event != nil && meeting.Event.ICalUID != "" {
+		return meeting.Event.ICalUID
+	}
+	if meeting.ID != "" {
+		return meeting.ID + "@zoom-go"
+	}
+	return fmt.Sprintf("%d@zoom-go", meeting.Start.Unix())
+}
+
+// icsOrganizerAddress returns the organizer's calendar address, which is required by ORGANIZER.
+func icsOrganizerAddress(meeting Meeting) string {
+	if meeting.Event != nil && meeting.Event.Organizer != nil && meeting.Event.Organizer.Email != "" {
+		return "mailto:" + meeting.Event.Organizer.Email
+	}
+	return "mailto:unknown@invalid"
+}
+
+// escapeICSText escapes a TEXT value per RFC 5545 section 3.3.11.
+func escapeICSText(s string) string {
+	return strings.NewReplacer(
+		`\`, `\\`,
+		";", `\;`,
+		",", `\,`,
+		"\r\n", `\n`,
+		"\n", `\n`,
+	).Replace(s)
+}
+
+// quoteICSParam quotes a parameter value, dropping characters which are not allowed in one.
+func quoteICSParam(s string) string {
+	return `"` + strings.NewReplacer(`"`, "", "\r", "", "\n", " ").Replace(s) + `"`
+}
+
+// writeICSLine writes a content line, folding it at 75 octets and terminating it with CRLF.
+// Continuation lines start with a space, which counts towards their 75 octets.
+func writeICSLine(w *bufio.Writer, line string) {
+	limit := icsMaxLineLength
+	for len(line) > limit {
+		cut := limit
+		// Don't split a multi-byte UTF-8 sequence.
+		for cut > 0 && line[cut]&0xC0 == 0x80 {
+			cut--
+		}
+		w.WriteString(line[:cut] + "\r\n ")
+		line = line[cut:]
+		limit = icsMaxLineLength - 1
+	}
+	w.WriteString(line + "\r\n")
+}
+
+// WriteCSV writes the meetings as CSV with a header row.
+func WriteCSV(w io.Writer, meetings []Meeting) error {
+	output := csv.NewWriter(w)
+	if err := output.Write([]string{"start", "title", "organizer", "url", "web_url", "calendar_url", "status", "end", "duration_minutes", "series_id", "instance"}); err != nil {
+		return errors.WithStack(err)
+	}
+	for _, meeting := range meetings {
+		start := ""
+		if !meeting.Start.IsZero() {
+			start = meeting.Start.In(meeting.location()).Format(time.RFC3339)
+		}
+		end, duration := "", ""
+		if !meeting.End.IsZero() {
+			end = meeting.End.In(meeting.location()).Format(time.RFC3339)
+		}
+		if meeting.Duration() > 0 {
+			duration = strconv.Itoa(int(meeting.Duration().Minutes()))
+		}
+		instance := ""
+		if meeting.Instance > 0 {
+			instance = strconv.Itoa(meeting.Instance)
+		}
+		record := []string{start, meeting.Title, meeting.Organizer, meeting.URL, meeting.WebURL, meeting.CalendarURL, meeting.RSVP(), end, duration, meeting.SeriesID, instance}
+		if err := output.Write(record); err != nil {
+			return errors.WithStack(err)
+		}
+	}
+	output.Flush()
+	return errors.WithStack(output.Error())
+}
+
+// WriteMarkdown writes the meetings as a Markdown agenda grouped by day in the local time zone.
+func WriteMarkdown(w io.Writer, meetings []Meeting) error {
+	output := bufio.NewWriter(w)
+
+	for i, day := range GroupByDay(meetings, meetingsLocation(meetings)) {
+		if i > 0 {
+			fmt.Fprintln(output)
+		}
+		if day.Date.IsZero() {
+			fmt.Fprint(output, "## Unscheduled\n\n")
+		} else {
+			fmt.Fprintf(output, "## %s\n\n", day.Date.Format("Monday, January 2, 2006"))
+		}
+
+		for _, meeting := range day.Meetings {
+			fmt.Fprint(output, "- ")
+			if meeting.AllDay {
+				fmt.Fprint(output, "All day: ")
+			} else if meeting.Duration() > 0 {
+				fmt.Fprintf(output, "%s–%s ", meeting.Start.In(meeting.location()).Format("15:04"), meeting.End.In(meeting.location()).Format("15:04"))
+			} else if !meeting.Start.IsZero() {
+				fmt.Fprintf(output, "%s ", meeting.Start.In(meeting.location()).Format("15:04"))
+			}
+
+			title := meeting.Title
+			if title == "" {
+				title = "Untitled meeting"
+			}
+			if meeting.WebURL != "" {
+				fmt.Fprintf(output, "[%s](%s)", escapeMarkdown(title), meeting.WebURL)
+			} else {
+				fmt.Fprint(output, escapeMarkdown(title))
+			}
+
+			if meeting.Organizer != "" {
+				fmt.Fprintf(output, ", organized by %s", escapeMarkdown(meeting.Organizer))
+			}
+			if rsvp := meeting.RSVP(); rsvp != "" {
+				fmt.Fprintf(output, " (%s)", rsvp)
+			}
+			fmt.Fprintln(output)
+		}
+	}
+
+	return errors.WithStack(output.Flush())
+}
+
+// WriteJSON writes the meetings as JSON, along with the conflicts between them found by FindConflicts
+// with the travel time.
+func WriteJSON(w io.Writer, meetings []Meeting, travelTime time.Duration) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return errors.WithStack(encoder.Encode(struct {
+		Meetings  []Meeting  `json:"meetings"`
+		Conflicts []Conflict `json:"conflicts"`
+	}{meetings, FindConflicts(meetings, travelTime)}))
+}
+
+// meetingsLocation returns the time zone the meetings are displayed in, that of the first meeting.
+func meetingsLocation(meetings []Meeting) *time.Location {
+	if len(meetings) == 0 {
+		return time.Local
+	}
+	return meetings[0].location()
+}
+
+// escapeMarkdown escapes characters which would otherwise be interpreted as Markdown link syntax.
+func escapeMarkdown(s string) string {
+	return strings.NewReplacer(`\`, `\\`, "[", `\[`, "]", `\]`, "*", `\*`, "_", `\_`).Replace(s)
+}
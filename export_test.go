@@ -0,0 +1,127 @@
+package zoom
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	calendar "google.golang.org/api/calendar/v3"
+)
+
+func testExportMeetings() []Meeting {
+	monday := time.Date(2018, time.October, 8, 9, 30, 0, 0, time.Local)
+	tuesday := time.Date(2018, time.October, 9, 14, 0, 0, 0, time.Local)
+
+	return []Meeting{
+		NewMeeting(&calendar.Event{
+			Id:        "abc123",
+			Summary:   "Standup, daily",
+			HtmlLink:  "https://calendar.google.com/event?eid=abc123",
+			Location:  "https://jithub.zoom.us/j/12345?pwd=secret",
+			Organizer: &calendar.EventOrganizer{DisplayName: "Kevin Jithub", Email: "kevin@jithub.com"},
+			Start:     &calendar.EventDateTime{DateTime: monday.Format(time.RFC3339)},
+			End:       &calendar.EventDateTime{DateTime: monday.Add(15 * time.Minute).Format(time.RFC3339)},
+		}),
+		NewMeeting(&calendar.Event{
+			Id:       "def456",
+			Summary:  "Design [review]",
+			Location: "https://jithub.zoom.us/my/foobar",
+			Start:    &calendar.EventDateTime{DateTime: tuesday.Format(time.RFC3339)},
+			Attendees: []*calendar.EventAttendee{
+				{Email: "me@jithub.com", Self: true, ResponseStatus: "tentative"},
+			},
+		}),
+	}
+}
+
+func TestExport_UnknownFormat(t *testing.T) {
+	err := Export(&bytes.Buffer{}, "pdf", nil, DefaultTravelTime)
+	assert.EqualError(t, err, `unknown export format "pdf", expected one of: ics, csv, markdown, json`)
+}
+
+func TestWriteICS(t *testing.T) {
+	var output bytes.Buffer
+	require.NoError(t, Export(&output, "ics", testExportMeetings(), DefaultTravelTime))
+
+	ics := output.String()
+	assert.True(t, strings.HasPrefix(ics, "BEGIN:VCALENDAR\r\nVERSION:2.0\r\n"))
+	assert.True(t, strings.HasSuffix(ics, "END:VEVENT\r\nEND:VCALENDAR\r\n"))
+	assert.Equal(t, 2, strings.Count(ics, "BEGIN:VEVENT\r\n"))
+
+	monday := time.Date(2018, time.October, 8, 9, 30, 0, 0, time.Local).UTC()
+	assert.Contains(t, ics, "UID:abc123@zoom-go\r\n")
+	assert.Contains(t, ics, "DTSTART:"+monday.Format(icsDateTimeFormat)+"\r\n")
+	assert.Contains(t, ics, "DTEND:"+monday.Add(15*time.Minute).Format(icsDateTimeFormat)+"\r\n")
+	assert.Contains(t, ics, "SUMMARY:Standup\\, daily\r\n")
+	assert.Contains(t, ics, "LOCATION:https://jithub.zoom.us/j/12345?pwd=secret\r\n")
+	assert.Contains(t, ics, "URL:https://jithub.zoom.us/j/12345?pwd=secret\r\n")
+	assert.Contains(t, ics, "ORGANIZER;CN=\"Kevin Jithub\":mailto:kevin@jithub.com\r\n")
+	assert.Contains(t, ics, "URL:https://jithub.zoom.us/my/foobar\r\n")
+
+	for _, line := range strings.Split(ics, "\r\n") {
+		assert.True(t, len(line) <= icsMaxLineLength, "line too long: %q", line)
+	}
+}
+
+func TestWriteICS_Folding(t *testing.T) {
+	start := time.Date(2018, time.October, 8, 9, 30, 0, 0, time.UTC)
+	calendarURL := "https://calendar.google.com/event?eid=" + strings.Repeat("abcdefghij", 20)
+	meeting := NewMeeting(&calendar.Event{
+		Id:       "abc123",
+		Summary:  strings.Repeat("Réunion d’équipe ", 10),
+		HtmlLink: calendarURL,
+		Location: "https://jithub.zoom.us/j/12345",
+		Start:    &calendar.EventDateTime{DateTime: start.Format(time.RFC3339)},
+	})
+
+	var output bytes.Buffer
+	require.NoError(t, Export(&output, "ics", []Meeting{meeting}, DefaultTravelTime))
+
+	ics := output.String()
+	for _, line := range strings.Split(ics, "\r\n") {
+		assert.True(t, len(line) <= icsMaxLineLength, "line longer than 75 octets: %q", line)
+		assert.True(t, utf8.ValidString(line), "line splits a character: %q", line)
+	}
+
+	unfolded := strings.Replace(ics, "\r\n ", "", -1)
+	assert.Contains(t, unfolded, "DESCRIPTION:Calendar event: "+calendarURL+"\r\n")
+	assert.Contains(t, unfolded, "SUMMARY:"+strings.Repeat("Réunion d’équipe ", 10)+"\r\n")
+}
+
+func TestWriteCSV(t *testing.T) {
+	var output bytes.Buffer
+	require.NoError(t, Export(&output, "csv", testExportMeetings(), DefaultTravelTime))
+
+	monday := time.Date(2018, time.October, 8, 9, 30, 0, 0, time.Local)
+	tuesday := time.Date(2018, time.October, 9, 14, 0, 0, 0, time.Local)
+	assert.Equal(t, "start,title,organizer,url,web_url,calendar_url,status,end,duration_minutes,series_id,instance\n"+
+		monday.Format(time.RFC3339)+`,"Standup, daily",Kevin Jithub,zoommtg://zoom.us/join?confno=12345&pwd=secret,https://jithub.zoom.us/j/12345?pwd=secret,https://calendar.google.com/event?eid=abc123,,`+
+		monday.Add(15*time.Minute).Format(time.RFC3339)+",15,,\n"+
+		tuesday.Format(time.RFC3339)+",Design [review],,https://jithub.zoom.us/my/foobar,https://jithub.zoom.us/my/foobar,,tentative,,,,\n",
+		output.String())
+}
+
+func TestWriteMarkdown(t *testing.T) {
+	var output bytes.Buffer
+	require.NoError(t, Export(&output, "markdown", testExportMeetings(), DefaultTravelTime))
+
+	assert.Equal(t, "## Monday, October 8, 2018\n\n"+
+		"- 09:30–09:45 [Standup, daily](https://jithub.zoom.us/j/12345?pwd=secret), organized by Kevin Jithub\n"+
+		"\n"+
+		"## Tuesday, October 9, 2018\n\n"+
+		"- 14:00 [Design \\[review\\]](https://jithub.zoom.us/my/foobar) (tentative)\n",
+		output.String())
+}
+
+func TestMeetingWebURLFromEvent(t *testing.T) {
+	webURL, ok := MeetingWebURLFromEvent(&calendar.Event{Description: "Join: https://jithub.zoom.us/j/12345?pwd=abc"})
+	require.True(t, ok)
+	assert.Equal(t, "https://jithub.zoom.us/j/12345?pwd=abc", webURL.String())
+
+	_, ok = MeetingWebURLFromEvent(&calendar.Event{Description: "https://github.com"})
+	assert.False(t, ok)
+}
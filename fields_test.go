@@ -0,0 +1,169 @@
+package zoom
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	calendar "google.golang.org/api/calendar/v3"
+)
+
+// countingWriter counts the bytes written to the wire.
+type countingWriter struct {
+	http.ResponseWriter
+	written *int64
+}
+
+func (w countingWriter) Write(b []byte) (int, error) {
+	atomic.AddInt64(w.written, int64(len(b)))
+	return w.ResponseWriter.Write(b)
+}
+
+// newFixtureServer serves a page of realistic events, honoring partial responses and gzip like Google does.
+func newFixtureServer(t testing.TB, eventCount int) (*httptest.Server, *int64) {
+	full := &calendar.Events{}
+	partial := &calendar.Events{}
+	for i := 0; i < eventCount; i++ {
+		start := time.Now().Add(time.Duration(i) * time.Hour)
+		event := &calendar.Event{
+			Id:          fmt.Sprintf("event%d", i),
+			Summary:     fmt.Sprintf("Meeting %d", i),
+			HtmlLink:    "https://calendar.google.com/event?eid=1234",
+			Description: strings.Repeat("Agenda item with a lot of detail. ", 60) + "https://jithub.zoom.us/j/12345",
+			Organizer:   &calendar.EventOrganizer{DisplayName: "Kevin Jithub", Email: "kevin@jithub.com"},
+			Start:       &calendar.EventDateTime{DateTime: start.Format(googleCalendarDateTimeFormat)},
+			End:         &calendar.EventDateTime{DateTime: start.Add(30 * time.Minute).Format(googleCalendarDateTimeFormat)},
+		}
+		partial.Items = append(partial.Items, event)
+
+		fullEvent := *event
+		for j := 0; j < 40; j++ {
+			fullEvent.Attendees = append(fullEvent.Attendees, &calendar.EventAttendee{
+				Email:          fmt.Sprintf("attendee%d@jithub.com", j),
+				DisplayName:    fmt.Sprintf("Attendee %d", j),
+				ResponseStatus: "accepted",
+			})
+		}
+		fullEvent.Attachments = []*calendar.EventAttachment{
+			{FileUrl: "https://docs.google.com/document/d/1234", Title: "Meeting notes", MimeType: "application/vnd.google-apps.document"},
+		}
+		fullEvent.Reminders = &calendar.EventReminders{UseDefault: true}
+		fullEvent.Etag = `"3181161784712000"`
+		full.Items = append(full.Items, &fullEvent)
+	}
+
+	fullBody, err := json.Marshal(full)
+	require.NoError(t, err)
+	partialBody, err := json.Marshal(partial)
+	require.NoError(t, err)
+
+	var written int64
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body := fullBody
+		if r.URL.Query().Get("fields") != "" {
+			body = partialBody
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		counter := countingWriter{ResponseWriter: w, written: &written}
+		if strings.Contains(r.Header.Get("Accept-Encoding"), "gzip") && strings.Contains(r.Header.Get("User-Agent"), "gzip") {
+			var compressed bytes.Buffer
+			gz := gzip.NewWriter(&compressed)
+			gz.Write(body)
+			gz.Close()
+
+			w.Header().Set("Content-Encoding", "gzip")
+			counter.Write(compressed.Bytes())
+			return
+		}
+		counter.Write(body)
+	}))
+	return server, &written
+}
+
+func TestNextEvents_PartialResponseAndGzip(t *testing.T) {
+	server, written := newFixtureServer(t, 10)
+	defer server.Close()
+
+	requests := 0
+	client := &http.Client{Transport: roundTripFunc(func(r *http.Request) (*http.Response, error) {
+		requests++
+		fields := r.URL.Query().Get("fields")
+		assert.Contains(t, fields, "items(")
+		assert.Contains(t, fields, "description")
+		assert.Contains(t, fields, "conferenceData/entryPoints(entryPointType,uri)")
+		assert.Contains(t, fields, "attendees(email,displayName,self,organizer,resource,responseStatus)")
+		assert.NotContains(t, fields, "attachments")
+		assert.Contains(t, r.Header.Get("User-Agent"), "gzip")
+		return http.DefaultTransport.RoundTrip(r)
+	})}
+
+	service, err := newCalendarService(client)
+	require.NoError(t, err)
+	service.BasePath = server.URL
+
+	events, err := NextEvents(service, 3)
+	require.NoError(t, err)
+	require.Len(t, events, 3)
+	assert.Nil(t, events[0].Attendees)
+	assert.Equal(t, 1, requests)
+	assert.True(t, *written < 2000, "expected a small gzipped response, got %d bytes", *written)
+}
+
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(r *http.Request) (*http.Response, error) {
+	return f(r)
+}
+
+// BenchmarkNextEvents compares fetching full, uncompressed event resources with the partial, gzipped
+// responses NextEvents requests. Run with:
+//     go test -bench=NextEvents -benchmem
+func BenchmarkNextEvents(b *testing.B) {
+	server, written := newFixtureServer(b, 50)
+	defer server.Close()
+
+	b.Run("full", func(b *testing.B) {
+		// Strip the partial response and disable compression to see what it would cost without them.
+		uncompressed := &http.Transport{DisableCompression: true}
+		service, err := calendar.New(&http.Client{Transport: roundTripFunc(func(r *http.Request) (*http.Response, error) {
+			query := r.URL.Query()
+			query.Del("fields")
+			r.URL.RawQuery = query.Encode()
+			return uncompressed.RoundTrip(r)
+		})})
+		require.NoError(b, err)
+		service.BasePath = server.URL
+
+		atomic.StoreInt64(written, 0)
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			_, err := NextEvents(service, 5)
+			require.NoError(b, err)
+		}
+		b.ReportMetric(float64(atomic.LoadInt64(written))/float64(b.N), "wire-bytes/op")
+	})
+
+	b.Run("partial+gzip", func(b *testing.B) {
+		service, err := newCalendarService(&http.Client{Transport: &http.Transport{}})
+		require.NoError(b, err)
+		service.BasePath = server.URL
+
+		atomic.StoreInt64(written, 0)
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			_, err := NextEvents(service, 5)
+			require.NoError(b, err)
+		}
+		b.ReportMetric(float64(atomic.LoadInt64(written))/float64(b.N), "wire-bytes/op")
+	})
+}
@@ -0,0 +1,230 @@
+package zoom
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+	calendar "google.golang.org/api/calendar/v3"
+)
+
+// The user's responses to a meeting invitation, as reported by the Calendar API.
+const (
+	ResponseAccepted    = "accepted"
+	ResponseTentative   = "tentative"
+	ResponseNeedsAction = "needsAction"
+	ResponseDeclined    = "declined"
+)
+
+// eventStatusCancelled is the status of a cancelled event.
+const eventStatusCancelled = "cancelled"
+
+// EventKind is the kind of a calendar event, which decides how it's handled.
+type EventKind string
+
+// The kinds of events.
+const (
+	// KindMeeting is an ordinary meeting.
+	KindMeeting EventKind = "meeting"
+	// KindAllDay is an event which lasts whole days rather than starting at a particular time.
+	KindAllDay EventKind = "all-day"
+	// KindOutOfOffice is an out-of-office block.
+	KindOutOfOffice EventKind = "out-of-office"
+	// KindFocusTime is a focus time block.
+	KindFocusTime EventKind = "focus-time"
+	// KindWorkingLocation is a working location, like "Home" or "Office".
+	KindWorkingLocation EventKind = "working-location"
+)
+
+// eventTypeKinds are the kinds of the Calendar API's special event types.
+var eventTypeKinds = map[string]EventKind{
+	"outOfOffice":     KindOutOfOffice,
+	"focusTime":       KindFocusTime,
+	"workingLocation": KindWorkingLocation,
+}
+
+// EventKindOf returns the kind of the event. Special event types, like out-of-office, take precedence over
+// all-day, since they're often all-day too.
+func EventKindOf(event *calendar.Event) EventKind {
+	if event == nil {
+		return KindMeeting
+	}
+	if kind, ok := eventTypeKinds[event.EventType]; ok {
+		return kind
+	}
+	if IsAllDay(event) {
+		return KindAllDay
+	}
+	return KindMeeting
+}
+
+// Handling is what to do with a kind of event.
+type Handling string
+
+// The ways events can be handled.
+const (
+	// HandleJoin lists the event and opens it automatically when it starts, like any meeting.
+	HandleJoin Handling = "join"
+	// HandleList lists the event, but never opens it automatically.
+	HandleList Handling = "list"
+	// HandleSkip leaves the event out entirely.
+	HandleSkip Handling = "skip"
+)
+
+// DefaultHandling is how each kind of event is handled unless configured otherwise. All-day events are listed,
+// but not joined at midnight, and out-of-office, focus time and working location blocks are skipped,
+// even if their descriptions contain Zoom links.
+var DefaultHandling = map[EventKind]Handling{
+	KindMeeting:         HandleJoin,
+	KindAllDay:          HandleList,
+	KindOutOfOffice:     HandleSkip,
+	KindFocusTime:       HandleSkip,
+	KindWorkingLocation: HandleSkip,
+}
+
+// ParseHandling parses a comma-separated list of kind=handling pairs, e.g. "all-day=skip,focus-time=list".
+func ParseHandling(s string) (map[EventKind]Handling, error) {
+	handling := map[EventKind]Handling{}
+	for _, pair := range strings.Split(s, ",") {
+		if strings.TrimSpace(pair) == "" {
+			continue
+		}
+		parts := strings.SplitN(pair, "=", 2)
+		if len(parts) != 2 {
+			return nil, errors.Errorf("invalid event handling %q, expected e.g. all-day=skip", pair)
+		}
+		kind, how := EventKind(strings.TrimSpace(parts[0])), Handling(strings.TrimSpace(parts[1]))
+		if err := validateHandling(kind, how); err != nil {
+			return nil, err
+		}
+		handling[kind] = how
+	}
+	return handling, nil
+}
+
+// validateHandling returns an error if the kind or handling is unknown.
+func validateHandling(kind EventKind, how Handling) error {
+	if _, ok := DefaultHandling[kind]; !ok {
+		kinds := []string{}
+		for kind := range DefaultHandling {
+			kinds = append(kinds, string(kind))
+		}
+		sort.Strings(kinds)
+		return errors.Errorf("unknown event kind %q, expected one of %s", kind, strings.Join(kinds, ", "))
+	}
+	switch how {
+	case HandleJoin, HandleList, HandleSkip:
+		return nil
+	default:
+		return errors.Errorf("unknown handling %q for %s events, expected join, list or skip", how, kind)
+	}
+}
+
+// Filter decides which Zoom meetings are returned and which are opened automatically.
+// The zero value is DefaultFilter.
+type Filter struct {
+	// IncludeDeclined includes meetings the user declined.
+	IncludeDeclined bool
+	// IncludeCancelled includes cancelled meetings.
+	IncludeCancelled bool
+	// Handling overrides DefaultHandling for some kinds of events.
+	Handling map[EventKind]Handling
+	// Series limits the meetings to the recurring series they match, if any are given.
+	Series []SeriesPattern
+	// ExcludeSeries leaves out the recurring series they match.
+	ExcludeSeries []SeriesPattern
+	// Rules decide which of the remaining meetings are included and opened automatically.
+	// The first rule which matches a meeting applies to it.
+	Rules []Rule
+}
+
+// DefaultFilter leaves out meetings the user declined, cancelled meetings, and the kinds of events
+// DefaultHandling skips.
+var DefaultFilter = Filter{}
+
+// AllMeetingsFilter allows every Zoom meeting: declined and cancelled ones, and every kind of event.
+// Meetings are fetched with it for the offline cache, so the cache is complete however they're filtered later.
+var AllMeetingsFilter = Filter{
+	IncludeDeclined:  true,
+	IncludeCancelled: true,
+	Handling: map[EventKind]Handling{
+		KindAllDay:          HandleList,
+		KindOutOfOffice:     HandleList,
+		KindFocusTime:       HandleList,
+		KindWorkingLocation: HandleList,
+	},
+}
+
+// Allows returns true if the event passes the filter.
+func (f Filter) Allows(event *calendar.Event) bool {
+	if event == nil {
+		return false
+	}
+	if event.Status == eventStatusCancelled && !f.IncludeCancelled {
+		return false
+	}
+	if SelfResponse(event) == ResponseDeclined && !f.IncludeDeclined {
+		return false
+	}
+	if len(f.Series) > 0 && !matchesAnySeries(f.Series, event) {
+		return false
+	}
+	if matchesAnySeries(f.ExcludeSeries, event) {
+		return false
+	}
+	if rule, ok := f.RuleFor(event); ok && rule.Action == RuleExclude {
+		return false
+	}
+	return f.HandlingOf(EventKindOf(event)) != HandleSkip
+}
+
+// AutoJoins returns true if the event passes the filter and may be opened automatically when it starts.
+func (f Filter) AutoJoins(event *calendar.Event) bool {
+	if rule, ok := f.RuleFor(event); ok && rule.Action == RuleNeverAutoJoin {
+		return false
+	}
+	return f.Allows(event) && f.HandlingOf(EventKindOf(event)) == HandleJoin
+}
+
+// HandlingOf returns how the kind of event is handled.
+func (f Filter) HandlingOf(kind EventKind) Handling {
+	if how, ok := f.Handling[kind]; ok {
+		return how
+	}
+	if how, ok := DefaultHandling[kind]; ok {
+		return how
+	}
+	return HandleJoin
+}
+
+// SelfResponse returns the user's response to the event's invitation,
+// or "" if the user isn't on the guest list, e.g. for their own events without guests.
+func SelfResponse(event *calendar.Event) string {
+	if event == nil {
+		return ""
+	}
+	for _, attendee := range event.Attendees {
+		if attendee.Self {
+			return attendee.ResponseStatus
+		}
+	}
+	return ""
+}
+
+// responseRank orders events by how committed the user is to them: accepted first, then tentative,
+// then those awaiting a response, then declined and cancelled ones.
+func responseRank(event *calendar.Event) int {
+	if event != nil && event.Status == eventStatusCancelled {
+		return 4
+	}
+	switch SelfResponse(event) {
+	case ResponseTentative:
+		return 1
+	case ResponseNeedsAction:
+		return 2
+	case ResponseDeclined:
+		return 3
+	default:
+		return 0
+	}
+}
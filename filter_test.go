@@ -0,0 +1,175 @@
+package zoom
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	calendar "google.golang.org/api/calendar/v3"
+)
+
+func TestFilter_Allows(t *testing.T) {
+	now := time.Now()
+	cancelled := testEvent("cancelled", "cancelled", now, withResponse("accepted"))
+	cancelled.Status = "cancelled"
+
+	testCases := []struct {
+		event    *calendar.Event
+		filter   Filter
+		expected bool
+	}{
+		{testEvent("mine", "mine", now), DefaultFilter, true},
+		{testEvent("accepted", "accepted", now, withResponse("accepted")), DefaultFilter, true},
+		{testEvent("tentative", "tentative", now, withResponse("tentative")), DefaultFilter, true},
+		{testEvent("needsAction", "needsAction", now, withResponse("needsAction")), DefaultFilter, true},
+		{testEvent("declined", "declined", now, withResponse("declined")), DefaultFilter, false},
+		{testEvent("declined", "declined", now, withResponse("declined")), Filter{IncludeDeclined: true}, true},
+		{cancelled, DefaultFilter, false},
+		{cancelled, Filter{IncludeCancelled: true}, true},
+		{nil, Filter{IncludeDeclined: true, IncludeCancelled: true}, false},
+	}
+	for _, testCase := range testCases {
+		name := "nil"
+		if testCase.event != nil {
+			name = fmt.Sprintf("%s %+v", testCase.event.Id, testCase.filter)
+		}
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, testCase.expected, testCase.filter.Allows(testCase.event))
+		})
+	}
+}
+
+func TestSortEventsByStart_RanksResponses(t *testing.T) {
+	now := time.Now()
+	events := []*calendar.Event{
+		testEvent("later", "later", now.Add(time.Hour), withResponse("accepted")),
+		testEvent("tentative", "tentative", now, withResponse("tentative")),
+		testEvent("needsAction", "needsAction", now, withResponse("needsAction")),
+		testEvent("accepted", "accepted", now, withResponse("accepted")),
+		testEvent("mine", "mine", now),
+	}
+	sortEventsByStart(events)
+	assert.Equal(t, []string{"accepted", "mine", "tentative", "needsAction", "later"}, summaries(events))
+}
+
+func TestGoogleSource_Filter(t *testing.T) {
+	mux := http.NewServeMux()
+	service, shutdown := newFakeGoogleCalendarService(t, mux)
+	defer shutdown()
+
+	now := time.Now()
+	showDeleted := []string{}
+	mux.HandleFunc("/calendars/primary/events", func(w http.ResponseWriter, r *http.Request) {
+		showDeleted = append(showDeleted, r.URL.Query().Get("showDeleted"))
+		cancelled := testEvent("cancelled", "cancelled", now.Add(time.Minute))
+		cancelled.Status = "cancelled"
+		fmt.Fprintf(w, `{"items": %s}`, syncTestEvents(t,
+			testEvent("declined", "declined", now.Add(time.Minute), withResponse("declined")),
+			cancelled,
+			testEvent("tentative", "tentative", now.Add(time.Minute), withResponse("tentative")),
+			testEvent("accepted", "accepted", now.Add(time.Minute), withResponse("accepted")),
+		))
+	})
+
+	source := NewGoogleSource(service)
+	events, err := source.NextEventsWithin(5, DefaultHorizon)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"accepted", "tentative"}, summaries(events))
+
+	source.Filter = Filter{IncludeDeclined: true, IncludeCancelled: true}
+	events, err = source.EventsBetween(now, now.Add(time.Hour))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"accepted", "tentative", "declined", "cancelled"}, summaries(events))
+
+	assert.Equal(t, []string{"false", "true"}, showDeleted)
+}
+
+func TestRenderMeeting_RSVP(t *testing.T) {
+	tmpl, err := ParseTemplate(DefaultTemplate)
+	require.NoError(t, err)
+
+	var output bytes.Buffer
+	require.NoError(t, RenderMeeting(&output, tmpl, NewMeeting(testEvent("standup", "standup", time.Now().Add(time.Hour), withResponse("needsAction")))))
+	assert.Contains(t, output.String(), "Status: not responded\n")
+
+	output.Reset()
+	require.NoError(t, RenderMeeting(&output, tmpl, NewMeeting(testEvent("standup", "standup", time.Now().Add(time.Hour), withResponse("accepted")))))
+	assert.NotContains(t, output.String(), "Status:")
+}
+
+func TestEventKindOf(t *testing.T) {
+	now := time.Now()
+	outOfOffice := testEvent("Vacation", "Vacation", now, allDay())
+	outOfOffice.EventType = "outOfOffice"
+	focusTime := testEvent("Focus time", "1", now)
+	focusTime.EventType = "focusTime"
+	workingLocation := testEvent("Home", "Home", now, allDay())
+	workingLocation.EventType = "workingLocation"
+	standup := testEvent("Standup", "2", now)
+	standup.EventType = "default"
+
+	testCases := []struct {
+		event    *calendar.Event
+		expected EventKind
+	}{
+		{nil, KindMeeting},
+		{standup, KindMeeting},
+		{testEvent("Offsite", "Offsite", now, allDay()), KindAllDay},
+		{outOfOffice, KindOutOfOffice},
+		{focusTime, KindFocusTime},
+		{workingLocation, KindWorkingLocation},
+	}
+	for _, testCase := range testCases {
+		t.Run(string(testCase.expected), func(t *testing.T) {
+			assert.Equal(t, testCase.expected, EventKindOf(testCase.event))
+		})
+	}
+}
+
+func TestFilter_Handling(t *testing.T) {
+	now := time.Now()
+	allDay := testEvent("Offsite", "Offsite", now, allDay())
+	focusTime := testEvent("Focus time", "1", now)
+	focusTime.EventType = "focusTime"
+	standup := testEvent("Standup", "2", now)
+
+	testCases := []struct {
+		name     string
+		event    *calendar.Event
+		filter   Filter
+		allows   bool
+		autoJoin bool
+	}{
+		{"meeting", standup, DefaultFilter, true, true},
+		{"all-day", allDay, DefaultFilter, true, false},
+		{"all-day skipped", allDay, Filter{Handling: map[EventKind]Handling{KindAllDay: HandleSkip}}, false, false},
+		{"all-day joined", allDay, Filter{Handling: map[EventKind]Handling{KindAllDay: HandleJoin}}, true, true},
+		{"focus time", focusTime, DefaultFilter, false, false},
+		{"focus time listed", focusTime, Filter{Handling: map[EventKind]Handling{KindFocusTime: HandleList}}, true, false},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			assert.Equal(t, testCase.allows, testCase.filter.Allows(testCase.event))
+			assert.Equal(t, testCase.autoJoin, testCase.filter.AutoJoins(testCase.event))
+		})
+	}
+}
+
+func TestParseHandling(t *testing.T) {
+	handling, err := ParseHandling(" all-day=skip, focus-time=list,")
+	require.NoError(t, err)
+	assert.Equal(t, map[EventKind]Handling{KindAllDay: HandleSkip, KindFocusTime: HandleList}, handling)
+
+	handling, err = ParseHandling("")
+	require.NoError(t, err)
+	assert.Empty(t, handling)
+
+	for _, invalid := range []string{"all-day", "birthday=skip", "all-day=ignore"} {
+		_, err := ParseHandling(invalid)
+		assert.Error(t, err, invalid)
+	}
+}
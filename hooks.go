@@ -0,0 +1,186 @@
+package zoom
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// MeetingHookEvent is when a meeting hook runs.
+type MeetingHookEvent string
+
+// The events meeting hooks run on.
+const (
+	// HookBeforeJoin runs just before a meeting is opened, e.g. to pause music or turn on Do Not Disturb.
+	HookBeforeJoin MeetingHookEvent = "before-join"
+	// HookAfterMeeting runs when a meeting ends, e.g. to open a notes template.
+	HookAfterMeeting MeetingHookEvent = "after-meeting"
+)
+
+// HookFailurePolicy is what happens when a meeting hook fails or times out.
+type HookFailurePolicy string
+
+// The failure policies.
+const (
+	// HookFailureWarn logs the failure and carries on. It's the default.
+	HookFailureWarn HookFailurePolicy = "warn"
+	// HookFailureIgnore carries on without logging the failure.
+	HookFailureIgnore HookFailurePolicy = "ignore"
+	// HookFailureAbort stops running hooks and, for before-join hooks, doesn't open the meeting.
+	HookFailureAbort HookFailurePolicy = "abort"
+)
+
+// MeetingHook is a shell command run before joining a meeting or after it ends. The HookPayload is written to the
+// command's standard input as JSON, and the meeting's details are set in environment variables: ZOOM_HOOK_EVENT,
+// ZOOM_MEETING_ID, ZOOM_MEETING_TITLE, ZOOM_MEETING_ORGANIZER, ZOOM_MEETING_START, ZOOM_MEETING_END,
+// ZOOM_MEETING_URL, ZOOM_MEETING_WEB_URL, ZOOM_MEETING_CALENDAR_URL and ZOOM_MEETING_LINKS, which lists the links in
+// the description, one per line.
+type MeetingHook struct {
+	Event   MeetingHookEvent
+	Command string
+	// Timeout defaults to DefaultHookTimeout.
+	Timeout time.Duration
+	// OnFailure defaults to HookFailureWarn.
+	OnFailure HookFailurePolicy
+}
+
+// HookPayload is the JSON payload sent to meeting hooks.
+type HookPayload struct {
+	Event   MeetingHookEvent `json:"event"`
+	Meeting Meeting          `json:"meeting"`
+	FiredAt time.Time        `json:"fired_at"`
+}
+
+// ParseMeetingHooks validates the hooks and fills in their default failure policy.
+func ParseMeetingHooks(hooks []MeetingHook) ([]MeetingHook, error) {
+	parsed := make([]MeetingHook, 0, len(hooks))
+	for _, hook := range hooks {
+		if hook.Command == "" {
+			return nil, errors.Errorf("hook for %s has no command", hook.Event)
+		}
+		switch hook.Event {
+		case HookBeforeJoin, HookAfterMeeting:
+		default:
+			return nil, errors.Errorf("unknown event %q for hook %q, expected before-join or after-meeting", hook.Event, hook.Command)
+		}
+		switch hook.OnFailure {
+		case "":
+			hook.OnFailure = HookFailureWarn
+		case HookFailureWarn, HookFailureIgnore, HookFailureAbort:
+		default:
+			return nil, errors.Errorf("unknown failure policy %q for hook %q, expected warn, ignore or abort", hook.OnFailure, hook.Command)
+		}
+		parsed = append(parsed, hook)
+	}
+	return parsed, nil
+}
+
+// Run runs the command for the meeting.
+func (h MeetingHook) Run(ctx context.Context, meeting Meeting, now time.Time) error {
+	payload, err := json.Marshal(HookPayload{Event: h.Event, Meeting: meeting, FiredAt: now})
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, hookTimeout(h.Timeout))
+	defer cancel()
+
+	start, end := "", ""
+	if !meeting.Start.IsZero() {
+		start = meeting.Start.Format(time.RFC3339)
+	}
+	if !meeting.End.IsZero() {
+		end = meeting.End.Format(time.RFC3339)
+	}
+
+	cmd := exec.CommandContext(ctx, "sh", "-c", h.Command)
+	// Children left running after a timeout, like a backgrounded app, mustn't keep the hook waiting.
+	cmd.WaitDelay = time.Second
+	cmd.Stdin = bytes.NewReader(payload)
+	cmd.Env = append(os.Environ(),
+		"ZOOM_HOOK_EVENT="+string(h.Event),
+		"ZOOM_MEETING_ID="+meeting.ID,
+		"ZOOM_MEETING_TITLE="+meeting.Title,
+		"ZOOM_MEETING_ORGANIZER="+meeting.Organizer,
+		"ZOOM_MEETING_START="+start,
+		"ZOOM_MEETING_END="+end,
+		"ZOOM_MEETING_URL="+meeting.URL,
+		"ZOOM_MEETING_WEB_URL="+meeting.WebURL,
+		"ZOOM_MEETING_CALENDAR_URL="+meeting.CalendarURL,
+		"ZOOM_MEETING_LINKS="+strings.Join(meeting.Links, "\n"),
+	)
+
+	if output, err := cmd.CombinedOutput(); err != nil {
+		if ctx.Err() == context.DeadlineExceeded {
+			return errors.Errorf("%s hook %q timed out after %s", h.Event, h.Command, hookTimeout(h.Timeout))
+		}
+		return errors.Wrapf(err, "%s hook %q failed: %s", h.Event, h.Command, bytes.TrimSpace(output))
+	}
+	return nil
+}
+
+// RunMeetingHooks runs the hooks for the event in order. Failures are logged to the logger, if it's not nil,
+// unless the hook ignores them. If a hook whose policy is HookFailureAbort fails, no more hooks are run and
+// its error is returned.
+func RunMeetingHooks(ctx context.Context, hooks []MeetingHook, event MeetingHookEvent, meeting Meeting, now time.Time, logger *log.Logger) error {
+	for _, hook := range hooks {
+		if hook.Event != event {
+			continue
+		}
+		err := hook.Run(ctx, meeting, now)
+		if err == nil {
+			continue
+		}
+		switch hook.OnFailure {
+		case HookFailureAbort:
+			return err
+		case HookFailureIgnore:
+		default:
+			if logger != nil {
+				logger.Printf("%v", err)
+			}
+		}
+	}
+	return nil
+}
+
+// HookAbortedError is returned by JoinMeeting when a before-join hook whose policy is HookFailureAbort fails.
+type HookAbortedError struct {
+	Err error
+}
+
+func (e *HookAbortedError) Error() string {
+	return e.Err.Error()
+}
+
+// Unwrap returns the hook's error.
+func (e *HookAbortedError) Unwrap() error {
+	return e.Err
+}
+
+// JoinMeeting runs the before-join hooks for the meeting and then opens its join URL with the opener.
+// Every way of joining a meeting goes through it, so the hooks always run. If a hook aborts, the meeting
+// isn't opened and a *HookAbortedError is returned.
+func JoinMeeting(ctx context.Context, opener Opener, hooks []MeetingHook, meeting Meeting, now time.Time, logger *log.Logger) error {
+	if err := RunMeetingHooks(ctx, hooks, HookBeforeJoin, meeting, now, logger); err != nil {
+		return &HookAbortedError{Err: err}
+	}
+	return opener.Open(meeting.URL)
+}
+
+// hasMeetingHooks returns true if any of the hooks run on the event.
+func hasMeetingHooks(hooks []MeetingHook, event MeetingHookEvent) bool {
+	for _, hook := range hooks {
+		if hook.Event == event {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,182 @@
+package zoom
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseMeetingHooks(t *testing.T) {
+	hooks, err := ParseMeetingHooks([]MeetingHook{
+		{Event: HookBeforeJoin, Command: "osascript -e 'tell application \"Spotify\" to pause'"},
+		{Event: HookAfterMeeting, Command: "open notes.md", OnFailure: HookFailureIgnore},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, HookFailureWarn, hooks[0].OnFailure)
+	assert.Equal(t, HookFailureIgnore, hooks[1].OnFailure)
+
+	invalid := []MeetingHook{
+		{Event: HookBeforeJoin},
+		{Event: "during", Command: "true"},
+		{Event: HookBeforeJoin, Command: "true", OnFailure: "retry"},
+	}
+	for _, hook := range invalid {
+		_, err := ParseMeetingHooks([]MeetingHook{hook})
+		assert.Error(t, err, "%+v", hook)
+	}
+}
+
+func TestMeetingHook_Run(t *testing.T) {
+	dir := t.TempDir()
+	start := time.Date(2018, time.October, 10, 9, 0, 0, 0, time.UTC)
+	event := testEvent("standup", "1", start)
+	event.Description = "Agenda: https://docs.google.com/document/d/abc and https://example.com/board"
+	meeting := NewMeeting(event)
+
+	hook := MeetingHook{
+		Event:   HookBeforeJoin,
+		Command: "cd " + dir + ` && printf '%s|%s|%s|%s' "$ZOOM_HOOK_EVENT" "$ZOOM_MEETING_TITLE" "$ZOOM_MEETING_START" "$ZOOM_MEETING_LINKS" > env.txt && cat > payload.json`,
+	}
+	require.NoError(t, hook.Run(context.Background(), meeting, start))
+
+	env, err := ioutil.ReadFile(filepath.Join(dir, "env.txt"))
+	require.NoError(t, err)
+	assert.Equal(t, "before-join|standup|2018-10-10T09:00:00Z|https://docs.google.com/document/d/abc\nhttps://example.com/board", string(env))
+
+	b, err := ioutil.ReadFile(filepath.Join(dir, "payload.json"))
+	require.NoError(t, err)
+	payload := HookPayload{}
+	require.NoError(t, json.Unmarshal(b, &payload))
+	assert.Equal(t, HookBeforeJoin, payload.Event)
+	assert.Equal(t, "zoommtg://zoom.us/join?confno=1", payload.Meeting.URL)
+
+	slow := MeetingHook{Event: HookBeforeJoin, Command: "sleep 5", Timeout: 50 * time.Millisecond}
+	err = slow.Run(context.Background(), meeting, start)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "timed out")
+}
+
+func TestRunMeetingHooks(t *testing.T) {
+	dir := t.TempDir()
+	meeting := NewMeeting(testEvent("standup", "1", time.Now()))
+	logs := &bytes.Buffer{}
+	logger := log.New(logs, "", 0)
+
+	hooks := []MeetingHook{
+		{Event: HookBeforeJoin, Command: "echo warned >&2; exit 1", OnFailure: HookFailureWarn},
+		{Event: HookBeforeJoin, Command: "exit 1", OnFailure: HookFailureIgnore},
+		{Event: HookAfterMeeting, Command: "touch " + filepath.Join(dir, "after"), OnFailure: HookFailureWarn},
+		{Event: HookBeforeJoin, Command: "touch " + filepath.Join(dir, "before"), OnFailure: HookFailureWarn},
+	}
+	require.NoError(t, RunMeetingHooks(context.Background(), hooks, HookBeforeJoin, meeting, time.Now(), logger))
+	assert.FileExists(t, filepath.Join(dir, "before"))
+	assert.NoFileExists(t, filepath.Join(dir, "after"), "only the hooks for the event run")
+	assert.Contains(t, logs.String(), "warned")
+	assert.NotContains(t, logs.String(), `"exit 1" failed`)
+
+	aborting := []MeetingHook{
+		{Event: HookBeforeJoin, Command: "exit 2", OnFailure: HookFailureAbort},
+		{Event: HookBeforeJoin, Command: "touch " + filepath.Join(dir, "skipped")},
+	}
+	assert.Error(t, RunMeetingHooks(context.Background(), aborting, HookBeforeJoin, meeting, time.Now(), logger))
+	assert.NoFileExists(t, filepath.Join(dir, "skipped"))
+}
+
+func TestWatcher_Hooks(t *testing.T) {
+	dir := t.TempDir()
+	start := time.Date(2018, time.October, 10, 9, 0, 0, 0, time.UTC)
+	watcher, clock, source, opener, logs := newTestWatcher(start)
+	watcher.Hooks = []MeetingHook{
+		{Event: HookBeforeJoin, Command: `test "$ZOOM_MEETING_TITLE" != retro`, OnFailure: HookFailureAbort},
+		{Event: HookAfterMeeting, Command: `echo "$ZOOM_MEETING_TITLE" >> ` + filepath.Join(dir, "ended.txt")},
+	}
+	source.set(
+		testEvent("standup", "1", start.Add(10*time.Minute)),
+		testEvent("retro", "2", start.Add(20*time.Minute)),
+	)
+
+	for i := 0; i < 12; i++ {
+		watcher.tick()
+		clock.Advance(5 * time.Minute)
+	}
+
+	assert.Equal(t, []string{"zoommtg://zoom.us/join?confno=1"}, opener.opened, "the aborting hook stops retro being opened")
+	assert.Contains(t, logs.String(), `Not opening "retro"`)
+
+	ended, err := ioutil.ReadFile(filepath.Join(dir, "ended.txt"))
+	require.NoError(t, err)
+	assert.Equal(t, "standup\nretro\n", string(ended))
+}
+
+func TestJoinMeeting_Hooks(t *testing.T) {
+	dir := t.TempDir()
+	now := time.Now()
+	hooks := []MeetingHook{
+		{Event: HookBeforeJoin, Command: `test "$ZOOM_MEETING_TITLE" != retro`, OnFailure: HookFailureAbort},
+		{Event: HookBeforeJoin, Command: `echo "$ZOOM_MEETING_TITLE" >> ` + filepath.Join(dir, "joined.txt")},
+	}
+
+	// Joining from a notification runs the hooks.
+	opener := &fakeOpener{}
+	notifier := NewNotifySendNotifier(opener)
+	notifier.Hooks = hooks
+	notifier.run = func(name string, args ...string) ([]byte, error) {
+		return []byte("join\n"), nil
+	}
+	for _, title := range []string{"standup", "retro"} {
+		meeting := NewMeeting(testEvent(title, "1", now.Add(time.Minute)))
+		require.NoError(t, notifier.Notify(NewNotification(meeting, now)))
+		notifier.wg.Wait()
+	}
+	assert.Len(t, opener.opened, 1, "the aborting hook stops retro being opened")
+
+	// So does joining through the server.
+	server, source, serverOpener := newTestServer(now)
+	server.Hooks = hooks
+	source.set(testEvent("retro", "2", now.Add(time.Minute)))
+	w, body := serveTestRequest(t, server, http.MethodPost, "/meetings/next/join", "")
+	assert.Equal(t, http.StatusConflict, w.Code)
+	assert.NotEmpty(t, body["error"])
+	assert.Empty(t, serverOpener.opened)
+
+	source.set(testEvent("planning", "3", now.Add(time.Minute)))
+	w, _ = serveTestRequest(t, server, http.MethodPost, "/meetings/next/join", "")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Len(t, serverOpener.opened, 1)
+
+	joined, err := ioutil.ReadFile(filepath.Join(dir, "joined.txt"))
+	require.NoError(t, err)
+	assert.Equal(t, "standup\nplanning\n", string(joined))
+}
+
+func TestWatcher_AfterMeetingHooksForMeetingsNotJoined(t *testing.T) {
+	dir := t.TempDir()
+	start := time.Date(2018, time.October, 10, 9, 0, 0, 0, time.UTC)
+	watcher, clock, source, opener, _ := newTestWatcher(start)
+	rules, err := ParseRules([]Rule{{Title: "webinar", Action: RuleNeverAutoJoin}})
+	require.NoError(t, err)
+	watcher.Filter = Filter{Rules: rules}
+	watcher.Hooks = []MeetingHook{
+		{Event: HookAfterMeeting, Command: `echo "$ZOOM_MEETING_TITLE" >> ` + filepath.Join(dir, "ended.txt")},
+	}
+	source.set(testEvent("webinar", "1", start.Add(10*time.Minute), lasting(30*time.Minute)))
+
+	for i := 0; i < 12; i++ {
+		watcher.tick()
+		clock.Advance(5 * time.Minute)
+	}
+
+	assert.Empty(t, opener.opened)
+	ended, err := ioutil.ReadFile(filepath.Join(dir, "ended.txt"))
+	require.NoError(t, err)
+	assert.Equal(t, "webinar\n", string(ended))
+}
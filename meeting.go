@@ -0,0 +1,334 @@
+package zoom
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	calendar "google.golang.org/api/calendar/v3"
+)
+
+// defaultMeetingLength is how long a meeting without an end time is assumed to last.
+const defaultMeetingLength = 30 * time.Minute
+
+// Meeting is a calendar event along with the details needed to display and join it.
+type Meeting struct {
+	// Event is the underlying calendar event.
+	Event *calendar.Event `json:"-"`
+
+	ID          string    `json:"id"`
+	Title       string    `json:"title"`
+	Organizer   string    `json:"organizer,omitempty"`
+	Start       time.Time `json:"start"`
+	End         time.Time `json:"end"`
+	URL         string    `json:"url,omitempty"`
+	WebURL      string    `json:"web_url,omitempty"`
+	CalendarURL string    `json:"calendar_url,omitempty"`
+	// Links are the links in the description other than Zoom's, e.g. to the agenda.
+	Links []string `json:"links,omitempty"`
+
+	// Attendees are the people invited, excluding resources like rooms.
+	Attendees []Attendee `json:"attendees,omitempty"`
+	// Response is the user's own response to the invitation: "accepted", "tentative", "declined" or "needsAction".
+	// It's empty if the user isn't on the guest list, e.g. for their own events without guests.
+	Response string `json:"response,omitempty"`
+	// OrganizedByMe is true if the user organized the meeting.
+	OrganizedByMe bool `json:"organized_by_me,omitempty"`
+	// Status is the event's status: "confirmed", "tentative" or "cancelled".
+	Status string `json:"status,omitempty"`
+	// Kind is the kind of event, e.g. an ordinary meeting or an all-day event.
+	Kind EventKind `json:"kind"`
+	// AllDay is true if the meeting lasts whole days. Start and End are then midnight local time.
+	AllDay bool `json:"all_day,omitempty"`
+	// SeriesID is the ID of the recurring event the meeting is an instance of. It's empty for one-off meetings.
+	SeriesID string `json:"series_id,omitempty"`
+	// Instance is the meeting's position in its recurring series, starting at 1, if known. See AnnotateSeries.
+	Instance int `json:"instance,omitempty"`
+	// SeriesURL is the join URL the rest of the meeting's series uses, if it differs from URL. See AnnotateSeries.
+	SeriesURL string `json:"series_url,omitempty"`
+	// Provider is the host the meeting is on, e.g. "jithub.zoom.us".
+	Provider string `json:"provider,omitempty"`
+	// Place is where the meeting happens in person, e.g. a room, if anywhere.
+	Place string `json:"place,omitempty"`
+	// TimeZone is the IANA time zone the meeting was scheduled in, e.g. "Europe/London", if the event has one.
+	TimeZone string `json:"time_zone,omitempty"`
+
+	// Zone is the time zone the meeting's times are displayed in. It defaults to the local time zone.
+	Zone *time.Location `json:"-"`
+}
+
+// Attendee is a person invited to a meeting.
+type Attendee struct {
+	Name     string `json:"name,omitempty"`
+	Email    string `json:"email,omitempty"`
+	Response string `json:"response,omitempty"`
+}
+
+// NewMeeting extracts the meeting details from the calendar event.
+func NewMeeting(event *calendar.Event) Meeting {
+	if event == nil {
+		return Meeting{}
+	}
+
+	meeting := Meeting{
+		Event:       event,
+		ID:          event.Id,
+		Title:       event.Summary,
+		CalendarURL: event.HtmlLink,
+		Status:      event.Status,
+		SeriesID:    event.RecurringEventId,
+		Kind:        EventKindOf(event),
+		AllDay:      IsAllDay(event),
+	}
+
+	if event.Start != nil {
+		meeting.TimeZone = event.Start.TimeZone
+	}
+	meeting.Place = meetingPlace(event)
+	if links := extractLinks(event.Description); len(links) > 0 {
+		meeting.Links = links
+	}
+
+	if event.Organizer != nil && event.Organizer.DisplayName != "" {
+		meeting.Organizer = event.Organizer.DisplayName
+	} else if event.Creator != nil && event.Creator.DisplayName != "" {
+		meeting.Organizer = event.Creator.DisplayName
+	}
+
+	if event.Organizer != nil {
+		meeting.OrganizedByMe = event.Organizer.Self
+	}
+	for _, attendee := range event.Attendees {
+		if attendee.Self {
+			meeting.Response = attendee.ResponseStatus
+		}
+		if attendee.Resource {
+			continue
+		}
+		meeting.Attendees = append(meeting.Attendees, Attendee{
+			Name:     attendee.DisplayName,
+			Email:    attendee.Email,
+			Response: attendee.ResponseStatus,
+		})
+	}
+
+	if startTime, err := MeetingStartTime(event); err == nil {
+		meeting.Start = startTime
+	}
+	if endTime, err := MeetingEndTime(event); err == nil {
+		meeting.End = endTime
+	}
+
+	if meetingURL, ok := MeetingURLFromEvent(event); ok {
+		meeting.URL = meetingURL.String()
+	}
+	if webURL, ok := MeetingWebURLFromEvent(event); ok {
+		meeting.WebURL = webURL.String()
+		meeting.Provider = strings.ToLower(webURL.Hostname())
+	}
+
+	return meeting
+}
+
+// NewMeetings extracts the meeting details from each event.
+func NewMeetings(events []*calendar.Event) []Meeting {
+	meetings := make([]Meeting, 0, len(events))
+	for _, event := range events {
+		meetings = append(meetings, NewMeeting(event))
+	}
+	return meetings
+}
+
+// meetingPlace returns where the event happens in person: the rooms booked for it, or otherwise its location
+// without any links or mentions of Zoom.
+func meetingPlace(event *calendar.Event) string {
+	rooms := []string{}
+	for _, attendee := range event.Attendees {
+		if attendee.Resource && attendee.ResponseStatus != ResponseDeclined && attendee.DisplayName != "" {
+			rooms = append(rooms, attendee.DisplayName)
+		}
+	}
+	if len(rooms) > 0 {
+		return strings.Join(rooms, ", ")
+	}
+
+	parts := []string{}
+	location := urlRegexp.ReplaceAllString(event.Location, "")
+	for _, part := range strings.FieldsFunc(location, func(r rune) bool { return strings.ContainsRune(",;/|\n", r) }) {
+		part = strings.TrimSpace(part)
+		if part == "" || strings.Contains(strings.ToLower(part), "zoom") {
+			continue
+		}
+		parts = append(parts, part)
+	}
+	return strings.Join(parts, ", ")
+}
+
+// In returns the meeting with its times displayed in the time zone.
+func (m Meeting) In(loc *time.Location) Meeting {
+	m.Zone = loc
+	return m
+}
+
+// location returns the time zone the meeting's times are displayed in.
+func (m Meeting) location() *time.Location {
+	if m.Zone == nil {
+		return time.Local
+	}
+	return m.Zone
+}
+
+// Times returns when the meeting starts and ends in the time zone it's displayed in,
+// e.g. "Mon Oct 8 09:30–10:00 PDT". It's empty if the meeting doesn't have a start time.
+func (m Meeting) Times() string {
+	if m.Start.IsZero() {
+		return ""
+	}
+	if m.AllDay {
+		return formatDateRange(m.Start, m.End)
+	}
+	return formatTimeRange(m.Start, m.End, m.location())
+}
+
+// OriginalTimes returns when the meeting starts and ends in the time zone it was scheduled in,
+// e.g. "17:30–18:00 BST in Europe/London". It's empty unless that zone's clock differs from the displayed one.
+func (m Meeting) OriginalTimes() string {
+	if m.Start.IsZero() || m.AllDay || m.TimeZone == "" {
+		return ""
+	}
+	original, err := time.LoadLocation(m.TimeZone)
+	if err != nil {
+		return ""
+	}
+	if sameClock(m.Start, original, m.location()) && (m.Duration() == 0 || sameClock(m.End, original, m.location())) {
+		return ""
+	}
+	return formatTimeRange(m.Start, m.End, original) + " in " + m.TimeZone
+}
+
+// Recurrence describes the meeting's place in its recurring series, e.g. "instance 14 of a recurring series".
+// It's empty for one-off meetings.
+func (m Meeting) Recurrence() string {
+	switch {
+	case m.SeriesID == "":
+		return ""
+	case m.Instance > 0:
+		return fmt.Sprintf("instance %d of a recurring series", m.Instance)
+	default:
+		return "part of a recurring series"
+	}
+}
+
+// Summary returns the one-line summary of the meeting.
+func (m Meeting) Summary() string {
+	return MeetingSummary(m.Event)
+}
+
+// Until returns a human-friendly statement of when the meeting starts, e.g. "3 minutes from now".
+func (m Meeting) Until() string {
+	return HumanizedStartTime(m.Event)
+}
+
+// Started returns true if the meeting's start time has passed.
+func (m Meeting) Started() bool {
+	return !m.Start.IsZero() && time.Until(m.Start) < 0
+}
+
+// Duration returns how long the meeting lasts, or 0 if it doesn't have an end time.
+func (m Meeting) Duration() time.Duration {
+	if m.Start.IsZero() || !m.End.After(m.Start) {
+		return 0
+	}
+	return m.End.Sub(m.Start)
+}
+
+// Length returns a human-friendly statement of how long the meeting lasts, e.g. "1 hr 30 min".
+// It's empty if the meeting doesn't have an end time.
+func (m Meeting) Length() string {
+	if duration := m.Duration(); duration > 0 {
+		return formatDuration(duration)
+	}
+	return ""
+}
+
+// meetingEnd returns when the meeting ends, assuming meetings without an end time last defaultMeetingLength.
+func meetingEnd(meeting Meeting) time.Time {
+	if meeting.End.After(meeting.Start) {
+		return meeting.End
+	}
+	return meeting.Start.Add(defaultMeetingLength)
+}
+
+// InProgress returns true if the meeting has started but not ended yet.
+// Meetings without an end time are never in progress.
+func (m Meeting) InProgress() bool {
+	return m.inProgressAt(time.Now())
+}
+
+func (m Meeting) inProgressAt(now time.Time) bool {
+	return m.Duration() > 0 && !now.Before(m.Start) && now.Before(m.End)
+}
+
+// TimeLeft returns a human-friendly statement of how long is left of a meeting in progress, e.g. "35 min left".
+// It's empty if the meeting isn't in progress.
+func (m Meeting) TimeLeft() string {
+	now := time.Now()
+	if !m.inProgressAt(now) {
+		return ""
+	}
+	return formatDuration(m.End.Sub(now)) + " left"
+}
+
+// formatDuration formats the duration in whole minutes, rounding up, e.g. "35 min", "1 hr" or "2 hr 15 min".
+// Whole days, like those of all-day events, are formatted as days.
+func formatDuration(d time.Duration) string {
+	if d >= 24*time.Hour && d%(24*time.Hour) == 0 {
+		if days := int(d / (24 * time.Hour)); days > 1 {
+			return fmt.Sprintf("%d days", days)
+		}
+		return "1 day"
+	}
+	return formatHoursMinutes(d)
+}
+
+// formatHoursMinutes formats the duration in whole minutes, rounding up, e.g. "35 min" or "26 hr 15 min".
+func formatHoursMinutes(d time.Duration) string {
+	minutes := int(math.Ceil(d.Minutes()))
+	parts := []string{}
+	if minutes >= 60 {
+		parts = append(parts, fmt.Sprintf("%d hr", minutes/60))
+	}
+	if minutes%60 != 0 || minutes == 0 {
+		parts = append(parts, fmt.Sprintf("%d min", minutes%60))
+	}
+	return strings.Join(parts, " ")
+}
+
+// Accepted returns true if the user accepted the meeting, or organized it without inviting themselves.
+func (m Meeting) Accepted() bool {
+	return m.Response == "accepted" || (m.Response == "" && m.OrganizedByMe)
+}
+
+// RSVP describes the meeting's status if the user might not attend: "cancelled", "declined", "tentative"
+// or "not responded". It's empty for meetings the user accepted or organized.
+func (m Meeting) RSVP() string {
+	if m.Status == eventStatusCancelled {
+		return "cancelled"
+	}
+	switch m.Response {
+	case ResponseDeclined:
+		return "declined"
+	case ResponseTentative:
+		return "tentative"
+	case ResponseNeedsAction:
+		return "not responded"
+	default:
+		return ""
+	}
+}
+
+// Soon returns true if the meeting is less than 5 minutes from now or in progress, like IsMeetingSoon.
+func (m Meeting) Soon() bool {
+	return IsMeetingSoon(m.Event)
+}
@@ -0,0 +1,149 @@
+package zoom
+
+import (
+	"bytes"
+	"html"
+	"os"
+	"os/user"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"text/template"
+
+	"github.com/pkg/errors"
+)
+
+// DefaultNotesPath is the path pattern notes files are created at when none is configured.
+// Like meeting templates, it's a Go text/template rendered with the meeting.
+const DefaultNotesPath = "~/Notes/{{.Date}} {{.Title | filename}}.md"
+
+// DefaultNotesTemplate is the template notes files are created from when none is configured.
+const DefaultNotesTemplate = `# {{.Title}}
+
+- When: {{.Times}}
+{{with .Organizer}}- Organizer: {{.}}
+{{end}}{{with .WebURL}}- Join: {{.}}
+{{else}}{{with .URL}}- Join: {{.}}
+{{end}}{{end}}{{with .CalendarURL}}- Calendar event: {{.}}
+{{end}}{{with .Attendees}}
+## Attendees
+{{range .}}
+- {{with .Name}}{{.}}{{else}}{{.Email}}{{end}}{{end}}
+{{end}}{{with .Agenda}}
+## Agenda
+
+{{.}}
+{{end}}{{with .Links}}
+## Links
+{{range .}}
+- {{.}}{{end}}
+{{end}}
+## Notes
+
+`
+
+var (
+	// htmlBreakRegexp matches the HTML tags which end a line.
+	htmlBreakRegexp = regexp.MustCompile(`(?i)<br\s*/?>|</(p|div|li|h[1-6])>`)
+	// htmlListItemRegexp matches the start of a list item.
+	htmlListItemRegexp = regexp.MustCompile(`(?i)<li[^>]*>`)
+	// htmlTagRegexp matches any other HTML tag.
+	htmlTagRegexp = regexp.MustCompile(`<[^>]+>`)
+)
+
+// Date returns the day the meeting starts in the time zone it's displayed in, e.g. "2018-10-08".
+// It's empty if the meeting doesn't have a start time.
+func (m Meeting) Date() string {
+	if m.Start.IsZero() {
+		return ""
+	}
+	if m.AllDay {
+		return m.Start.Format("2006-01-02")
+	}
+	return m.Start.In(m.location()).Format("2006-01-02")
+}
+
+// Agenda returns the meeting's description as plain text, up to where the Zoom invitation starts.
+func (m Meeting) Agenda() string {
+	if m.Event == nil {
+		return ""
+	}
+	return meetingAgenda(m.Event.Description)
+}
+
+// meetingAgenda converts the description from HTML to plain text and cuts it off at the Zoom invitation
+// which is usually pasted below the agenda, i.e. at the first Zoom link, "Join Zoom Meeting" or Google's
+// separator line.
+func meetingAgenda(description string) string {
+	text := htmlBreakRegexp.ReplaceAllString(description, "\n")
+	text = htmlListItemRegexp.ReplaceAllString(text, "- ")
+	text = html.UnescapeString(htmlTagRegexp.ReplaceAllString(text, ""))
+
+	lines := []string{}
+	for _, line := range strings.Split(text, "\n") {
+		line = strings.TrimRight(line, " \t\r")
+		trimmed := strings.TrimSpace(line)
+		lower := strings.ToLower(trimmed)
+		if strings.Contains(lower, "zoom.us") || strings.HasPrefix(lower, "join zoom meeting") || strings.HasPrefix(trimmed, "-::~:~::~") || strings.HasPrefix(trimmed, "──") {
+			break
+		}
+		// Runs of blank lines are collapsed into one.
+		if trimmed == "" && (len(lines) == 0 || lines[len(lines)-1] == "") {
+			continue
+		}
+		lines = append(lines, line)
+	}
+	return strings.TrimSpace(strings.Join(lines, "\n"))
+}
+
+// NotesPath renders the path pattern for the meeting, expanding a leading ~ to the home directory.
+func NotesPath(pattern string, meeting Meeting) (string, error) {
+	tmpl, err := template.New("path").Funcs(TemplateFuncs).Parse(pattern)
+	if err != nil {
+		return "", errors.Wrap(err, "invalid notes path")
+	}
+	var path bytes.Buffer
+	if err := tmpl.Execute(&path, meeting); err != nil {
+		return "", errors.WithStack(err)
+	}
+
+	return ExpandHome(path.String())
+}
+
+// ExpandHome expands a leading ~ in the path to the user's home directory.
+func ExpandHome(path string) (string, error) {
+	if path != "~" && !strings.HasPrefix(path, "~/") {
+		return filepath.Clean(path), nil
+	}
+	usr, err := user.Current()
+	if err != nil {
+		return "", errors.WithStack(err)
+	}
+	return filepath.Join(usr.HomeDir, strings.TrimPrefix(path, "~")), nil
+}
+
+// WriteNotes creates the notes file at path from the template, creating its directory if needed.
+// An existing file is never overwritten: created is false if the file already exists.
+func WriteNotes(path string, tmpl *template.Template, meeting Meeting) (created bool, err error) {
+	var notes bytes.Buffer
+	if err := RenderMeeting(&notes, tmpl, meeting); err != nil {
+		return false, err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return false, errors.WithStack(err)
+	}
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+	if os.IsExist(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, errors.WithStack(err)
+	}
+
+	if _, err := notes.WriteTo(file); err != nil {
+		file.Close()
+		return false, errors.WithStack(err)
+	}
+	return true, errors.WithStack(file.Close())
+}
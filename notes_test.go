@@ -0,0 +1,85 @@
+package zoom
+
+import (
+	"io/ioutil"
+	"os/user"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	calendar "google.golang.org/api/calendar/v3"
+)
+
+func notesTestMeeting() Meeting {
+	start := time.Date(2018, time.October, 8, 9, 30, 0, 0, time.UTC)
+	event := testEvent("Design review: Q4/Q1", "12345", start)
+	event.End = &calendar.EventDateTime{DateTime: start.Add(30 * time.Minute).Format(googleCalendarDateTimeFormat)}
+	event.HtmlLink = "https://calendar.google.com/event?eid=abc123"
+	event.Organizer = &calendar.EventOrganizer{DisplayName: "Kevin Jithub"}
+	event.Attendees = []*calendar.EventAttendee{
+		{DisplayName: "Kevin Jithub", Email: "kevin@example.com"},
+		{Email: "dana@example.com"},
+		{DisplayName: "Room 101", Email: "room@resource.calendar.google.com", Resource: true},
+	}
+	event.Description = `Agenda:<br><ul><li>Review the <a href="https://docs.google.com/document/d/abc">design doc</a></li>` +
+		`<li>Decide on Q1 &amp; Q2</li></ul><br><br>` +
+		`──────────<br>Join Zoom Meeting<br>https://jithub.zoom.us/j/12345<br>Meeting ID: 123 45`
+	return NewMeeting(event).In(time.UTC)
+}
+
+func TestMeeting_Agenda(t *testing.T) {
+	assert.Equal(t, "Agenda:\n- Review the design doc\n- Decide on Q1 & Q2", notesTestMeeting().Agenda())
+	assert.Equal(t, "Discuss the roadmap\n\nBring questions", meetingAgenda("Discuss the roadmap\n\n\n\nBring questions\nJoin Zoom Meeting\nhttps://zoom.us/j/1"))
+	assert.Empty(t, meetingAgenda("https://jithub.zoom.us/j/1"))
+}
+
+func TestNotesPath(t *testing.T) {
+	meeting := notesTestMeeting()
+
+	path, err := NotesPath("/vault/{{.Date}} {{.Title | filename}}.md", meeting)
+	require.NoError(t, err)
+	assert.Equal(t, "/vault/2018-10-08 Design review- Q4-Q1.md", path)
+
+	usr, err := user.Current()
+	require.NoError(t, err)
+	path, err = NotesPath("~/Notes/{{.Date}}.md", meeting)
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(usr.HomeDir, "Notes", "2018-10-08.md"), path)
+
+	_, err = NotesPath("{{.Date", meeting)
+	assert.Error(t, err)
+}
+
+func TestWriteNotes(t *testing.T) {
+	meeting := notesTestMeeting()
+	tmpl, err := ParseTemplate(DefaultNotesTemplate)
+	require.NoError(t, err)
+	path := filepath.Join(t.TempDir(), "meetings", "notes.md")
+
+	created, err := WriteNotes(path, tmpl, meeting)
+	require.NoError(t, err)
+	assert.True(t, created)
+
+	notes, err := ioutil.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "# Design review: Q4/Q1\n\n"+
+		"- When: Mon Oct 8 09:30–10:00 UTC\n"+
+		"- Organizer: Kevin Jithub\n"+
+		"- Join: https://jithub.zoom.us/j/12345\n"+
+		"- Calendar event: https://calendar.google.com/event?eid=abc123\n"+
+		"\n## Attendees\n\n- Kevin Jithub\n- dana@example.com\n"+
+		"\n## Agenda\n\nAgenda:\n- Review the design doc\n- Decide on Q1 & Q2\n"+
+		"\n## Links\n\n- https://docs.google.com/document/d/abc\n"+
+		"\n## Notes\n\n", string(notes))
+
+	// An existing file, e.g. with notes already taken, is left alone.
+	require.NoError(t, ioutil.WriteFile(path, []byte("my notes"), 0600))
+	created, err = WriteNotes(path, tmpl, meeting)
+	require.NoError(t, err)
+	assert.False(t, created)
+	notes, err = ioutil.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "my notes", string(notes))
+}
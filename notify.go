@@ -0,0 +1,169 @@
+package zoom
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// DefaultNotifyBefore are how long before a meeting starts the Watcher shows notifications.
+var DefaultNotifyBefore = []time.Duration{5 * time.Minute, time.Minute}
+
+// Notification is a desktop notification about an upcoming meeting.
+type Notification struct {
+	Title string
+	Body  string
+	// JoinURL is opened by the notification's "Join" action, if the notifier supports actions.
+	JoinURL string
+	Meeting Meeting
+}
+
+// NewNotification returns the notification for a meeting which starts soon.
+func NewNotification(meeting Meeting, now time.Time) Notification {
+	title := "Zoom meeting starting now"
+	if until := meeting.Start.Sub(now).Round(time.Minute); until >= time.Minute {
+		title = fmt.Sprintf("Zoom meeting in %s", humanizeMinutes(until))
+	}
+
+	return Notification{
+		Title:   title,
+		Body:    fmt.Sprintf("%s\nStarts at %s.", meeting.Summary(), meeting.Start.Local().Format(time.Kitchen)),
+		JoinURL: meeting.URL,
+		Meeting: meeting,
+	}
+}
+
+// humanizeMinutes formats a whole number of minutes, e.g. "1 minute" or "5 minutes".
+func humanizeMinutes(d time.Duration) string {
+	minutes := int(d / time.Minute)
+	if minutes == 1 {
+		return "1 minute"
+	}
+	return fmt.Sprintf("%d minutes", minutes)
+}
+
+// Notifier shows desktop notifications.
+type Notifier interface {
+	Notify(Notification) error
+}
+
+// NotifySendNotifier shows freedesktop notifications over D-Bus using the notify-send command.
+type NotifySendNotifier struct {
+	// Opener opens the join URL when the notification's "Join" action is clicked.
+	// If it's nil, notifications don't have a "Join" action.
+	Opener Opener
+	// Hooks run before the meeting is opened from a notification, like when the Watcher opens it.
+	Hooks []MeetingHook
+	// Logger logs hook failures and meetings which couldn't be opened. If it's nil, they aren't logged.
+	Logger *log.Logger
+
+	// run runs a command and returns its standard output. It's a field so tests can fake it.
+	run func(name string, args ...string) ([]byte, error)
+	// wg tracks notifications waiting for an action, so tests can wait for them.
+	wg sync.WaitGroup
+}
+
+// NewNotifySendNotifier returns a NotifySendNotifier whose "Join" action opens URLs with the opener.
+func NewNotifySendNotifier(opener Opener) *NotifySendNotifier {
+	return &NotifySendNotifier{Opener: opener}
+}
+
+// Notify shows the notification. If it has a "Join" action, notify-send waits for the notification to be
+// dismissed in the background, opening the join URL if "Join" was clicked.
+func (n *NotifySendNotifier) Notify(notification Notification) error {
+	args := []string{"--app-name=zoom", "--urgency=normal", notification.Title, notification.Body}
+	if n.Opener == nil || notification.JoinURL == "" {
+		_, err := n.runCommand("notify-send", args...)
+		return err
+	}
+
+	n.wg.Add(1)
+	go func() {
+		defer n.wg.Done()
+
+		output, err := n.runCommand("notify-send", append([]string{"--action=join=Join"}, args...)...)
+		if err != nil {
+			// Older versions of notify-send don't support actions.
+			n.runCommand("notify-send", args...)
+			return
+		}
+		if strings.TrimSpace(string(output)) != "join" {
+			return
+		}
+		meeting := notification.Meeting
+		meeting.URL = notification.JoinURL
+		if err := JoinMeeting(context.Background(), n.Opener, n.Hooks, meeting, time.Now(), n.Logger); err != nil && n.Logger != nil {
+			n.Logger.Printf("Unable to open %q: %v", meeting.Title, err)
+		}
+	}()
+	return nil
+}
+
+func (n *NotifySendNotifier) runCommand(name string, args ...string) ([]byte, error) {
+	if n.run != nil {
+		return n.run(name, args...)
+	}
+	output, err := exec.Command(name, args...).Output()
+	return output, errors.WithStack(err)
+}
+
+// RecordingNotifier is a Notifier which records notifications instead of showing them, for tests.
+type RecordingNotifier struct {
+	mu            sync.Mutex
+	Notifications []Notification
+}
+
+// Notify records the notification.
+func (n *RecordingNotifier) Notify(notification Notification) error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.Notifications = append(n.Notifications, notification)
+	return nil
+}
+
+// QuietHours is a daily range of local times, like 22:00-07:00, during which notifications aren't shown.
+// The zero value has no quiet hours.
+type QuietHours struct {
+	// Start and End are offsets from midnight. If End is before Start, the range wraps past midnight.
+	Start, End time.Duration
+}
+
+// ParseQuietHours parses quiet hours in the form "22:00-07:00". An empty string means no quiet hours.
+func ParseQuietHours(s string) (QuietHours, error) {
+	if strings.TrimSpace(s) == "" {
+		return QuietHours{}, nil
+	}
+
+	start, end, ok := parseClockRange(s)
+	if !ok {
+		return QuietHours{}, errors.Errorf("invalid quiet hours %q, expected e.g. 22:00-07:00", s)
+	}
+	return QuietHours{Start: start, End: end}, nil
+}
+
+// Contains returns true if t's local time of day is within the quiet hours.
+func (q QuietHours) Contains(t time.Time) bool {
+	if q.Start == q.End {
+		return false
+	}
+
+	local := t.Local()
+	sinceMidnight := local.Sub(StartOfDay(local))
+	if q.Start < q.End {
+		return q.Start <= sinceMidnight && sinceMidnight < q.End
+	}
+	return sinceMidnight >= q.Start || sinceMidnight < q.End
+}
+
+func (q QuietHours) String() string {
+	if q.Start == q.End {
+		return ""
+	}
+	return formatClockRange(q.Start, q.End)
+}
@@ -0,0 +1,146 @@
+package zoom
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	calendar "google.golang.org/api/calendar/v3"
+)
+
+func TestWatcher_Notifies(t *testing.T) {
+	start := time.Date(2018, time.October, 10, 9, 0, 0, 0, time.Local)
+	watcher, clock, source, opener, _ := newTestWatcher(start)
+	notifier := &RecordingNotifier{}
+	watcher.Notifier = notifier
+	watcher.Opener = nil
+
+	event := testEvent("standup", "1", start.Add(10*time.Minute))
+	event.Organizer = &calendar.EventOrganizer{DisplayName: "Kevin Jithub"}
+	source.set(event)
+
+	watcher.tick()
+	assert.Empty(t, notifier.Notifications)
+
+	clock.Advance(5 * time.Minute)
+	watcher.tick()
+	require.Len(t, notifier.Notifications, 1)
+	assert.Equal(t, "Zoom meeting in 5 minutes", notifier.Notifications[0].Title)
+	assert.Equal(t, "Your next meeting is \"standup\", organized by Kevin Jithub.\nStarts at 9:10AM.", notifier.Notifications[0].Body)
+	assert.Equal(t, "zoommtg://zoom.us/join?confno=1", notifier.Notifications[0].JoinURL)
+
+	clock.Advance(4 * time.Minute)
+	watcher.tick()
+	require.Len(t, notifier.Notifications, 2)
+	assert.Equal(t, "Zoom meeting in 1 minute", notifier.Notifications[1].Title)
+
+	// Without an opener, the meeting isn't opened.
+	clock.Advance(2 * time.Minute)
+	watcher.tick()
+	assert.Empty(t, opener.opened)
+	assert.Len(t, notifier.Notifications, 2)
+}
+
+func TestWatcher_NotifiesOnceWhenStartedLate(t *testing.T) {
+	start := time.Date(2018, time.October, 10, 9, 0, 0, 0, time.Local)
+	watcher, clock, source, _, _ := newTestWatcher(start)
+	notifier := &RecordingNotifier{}
+	watcher.Notifier = notifier
+	watcher.Opener = nil
+	source.set(testEvent("standup", "1", start.Add(30*time.Second)))
+
+	// Starting inside the one minute window only shows the one minute notification, not the five minute one too.
+	watcher.tick()
+	require.Len(t, notifier.Notifications, 1)
+	assert.Equal(t, "Zoom meeting in 1 minute", notifier.Notifications[0].Title)
+
+	clock.Advance(10 * time.Second)
+	watcher.tick()
+	assert.Len(t, notifier.Notifications, 1)
+}
+
+func TestWatcher_QuietHours(t *testing.T) {
+	start := time.Date(2018, time.October, 10, 22, 30, 0, 0, time.Local)
+	watcher, clock, source, opener, logs := newTestWatcher(start)
+	notifier := &RecordingNotifier{}
+	watcher.Notifier = notifier
+	watcher.NotifyBefore = []time.Duration{5 * time.Minute}
+	watcher.QuietHours = QuietHours{Start: 22 * time.Hour, End: 7 * time.Hour}
+	source.set(testEvent("late night", "1", start.Add(10*time.Minute)))
+
+	watcher.tick()
+	clock.Advance(10 * time.Minute)
+	watcher.tick()
+	assert.Empty(t, notifier.Notifications)
+	assert.Contains(t, logs.String(), `Not notifying about "late night" during quiet hours`)
+
+	// Quiet hours only affect notifications.
+	assert.Len(t, opener.opened, 1)
+}
+
+func TestParseQuietHours(t *testing.T) {
+	quietHours, err := ParseQuietHours("22:00-07:30")
+	require.NoError(t, err)
+	assert.Equal(t, QuietHours{Start: 22 * time.Hour, End: 7*time.Hour + 30*time.Minute}, quietHours)
+	assert.Equal(t, "22:00-07:30", quietHours.String())
+
+	day := time.Date(2018, time.October, 10, 0, 0, 0, 0, time.Local)
+	assert.True(t, quietHours.Contains(day.Add(23*time.Hour)))
+	assert.True(t, quietHours.Contains(day.Add(3*time.Hour)))
+	assert.False(t, quietHours.Contains(day.Add(7*time.Hour+30*time.Minute)))
+	assert.False(t, quietHours.Contains(day.Add(12*time.Hour)))
+
+	lunch, err := ParseQuietHours("12:00-13:00")
+	require.NoError(t, err)
+	assert.True(t, lunch.Contains(day.Add(12*time.Hour+30*time.Minute)))
+	assert.False(t, lunch.Contains(day.Add(13*time.Hour)))
+
+	none, err := ParseQuietHours("")
+	require.NoError(t, err)
+	assert.False(t, none.Contains(day))
+
+	_, err = ParseQuietHours("late")
+	assert.EqualError(t, err, `invalid quiet hours "late", expected e.g. 22:00-07:00`)
+}
+
+func TestNotifySendNotifier(t *testing.T) {
+	opener := &fakeOpener{}
+	notifier := NewNotifySendNotifier(opener)
+
+	commands := [][]string{}
+	notifier.run = func(name string, args ...string) ([]byte, error) {
+		commands = append(commands, append([]string{name}, args...))
+		return []byte("join\n"), nil
+	}
+
+	require.NoError(t, notifier.Notify(Notification{Title: "Zoom meeting in 5 minutes", Body: "Standup", JoinURL: "zoommtg://zoom.us/join?confno=1"}))
+	notifier.wg.Wait()
+
+	assert.Equal(t, [][]string{
+		{"notify-send", "--action=join=Join", "--app-name=zoom", "--urgency=normal", "Zoom meeting in 5 minutes", "Standup"},
+	}, commands)
+	assert.Equal(t, []string{"zoommtg://zoom.us/join?confno=1"}, opener.opened)
+}
+
+func TestNotifySendNotifier_WithoutActions(t *testing.T) {
+	opener := &fakeOpener{}
+	notifier := NewNotifySendNotifier(opener)
+
+	commands := [][]string{}
+	notifier.run = func(name string, args ...string) ([]byte, error) {
+		commands = append(commands, append([]string{name}, args...))
+		if args[0] == "--action=join=Join" {
+			return nil, errors.New("Unknown option --action=join=Join")
+		}
+		return nil, nil
+	}
+
+	require.NoError(t, notifier.Notify(Notification{Title: "Zoom meeting in 1 minute", Body: "Standup", JoinURL: "zoommtg://zoom.us/join?confno=1"}))
+	notifier.wg.Wait()
+
+	require.Len(t, commands, 2)
+	assert.Equal(t, []string{"notify-send", "--app-name=zoom", "--urgency=normal", "Zoom meeting in 1 minute", "Standup"}, commands[1])
+	assert.Empty(t, opener.opened)
+}
@@ -0,0 +1,329 @@
+package zoom
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/pkg/errors"
+)
+
+// ErrPickCancelled indicates that the user didn't pick a meeting.
+var ErrPickCancelled = errors.New("no meeting picked")
+
+// JoinableMeetings returns the meetings which can be joined now: those the filter opens automatically with a join URL
+// which start or started less than 5 minutes from now, or are still in progress, like IsMeetingSoon.
+// Meetings starting around now come before those which started earlier. Within each, meetings the user
+// accepted come first, then tentative ones, then those awaiting a response.
+func JoinableMeetings(meetings []Meeting, now time.Time, filter Filter) []Meeting {
+	starting, inProgress := []Meeting{}, []Meeting{}
+	for _, meeting := range meetings {
+		if meeting.URL == "" || meeting.Start.IsZero() || !filter.AutoJoins(meeting.Event) {
+			continue
+		}
+		if until := meeting.Start.Sub(now); -5*time.Minute < until && until < 5*time.Minute {
+			starting = append(starting, meeting)
+		} else if meeting.inProgressAt(now) {
+			inProgress = append(inProgress, meeting)
+		}
+	}
+	sortMeetingsByResponse(starting)
+	sortMeetingsByResponse(inProgress)
+	return append(starting, inProgress...)
+}
+
+// sortMeetingsByResponse sorts the meetings by the user's response, keeping meetings with the same response in order.
+func sortMeetingsByResponse(meetings []Meeting) {
+	sort.SliceStable(meetings, func(i, j int) bool {
+		return responseRank(meetings[i].Event) < responseRank(meetings[j].Event)
+	})
+}
+
+// PickPolicy chooses which of several joinable meetings to open without asking.
+type PickPolicy string
+
+// The pick policies.
+const (
+	// PickFirst picks the first meeting, which is the best one in the order the meetings are given in,
+	// e.g. the meeting starting now which the user accepted, as ordered by JoinableMeetings.
+	PickFirst PickPolicy = "first"
+	// PickMostAttendees picks the meeting with the most attendees.
+	PickMostAttendees PickPolicy = "most-attendees"
+	// PickAccepted picks the first meeting the user accepted, if any.
+	PickAccepted PickPolicy = "accepted"
+	// PickOrganizedByMe picks the first meeting the user organized, if any.
+	PickOrganizedByMe PickPolicy = "organized-by-me"
+)
+
+// PickPolicies are the supported pick policies.
+var PickPolicies = []PickPolicy{PickFirst, PickMostAttendees, PickAccepted, PickOrganizedByMe}
+
+// ParsePickPolicy returns the pick policy with the name. An empty name is PickFirst.
+func ParsePickPolicy(name string) (PickPolicy, error) {
+	if name == "" {
+		return PickFirst, nil
+	}
+	for _, policy := range PickPolicies {
+		if string(policy) == name {
+			return policy, nil
+		}
+	}
+
+	names := make([]string, 0, len(PickPolicies))
+	for _, policy := range PickPolicies {
+		names = append(names, string(policy))
+	}
+	return "", errors.Errorf("unknown pick policy %q, expected one of %s", name, strings.Join(names, ", "))
+}
+
+// Pick returns the meeting the policy chooses, which is false if no meeting meets the policy.
+// The meetings are expected best first, like JoinableMeetings or SearchMeetings return them;
+// policies which pick the first of several matching meetings pick the best of them.
+func (p PickPolicy) Pick(meetings []Meeting) (Meeting, bool) {
+	if len(meetings) == 0 {
+		return Meeting{}, false
+	}
+
+	switch p {
+	case PickMostAttendees:
+		best := meetings[0]
+		for _, meeting := range meetings[1:] {
+			if len(meeting.Attendees) > len(best.Attendees) {
+				best = meeting
+			}
+		}
+		return best, true
+	case PickAccepted:
+		for _, meeting := range meetings {
+			if meeting.Accepted() {
+				return meeting, true
+			}
+		}
+		return Meeting{}, false
+	case PickOrganizedByMe:
+		for _, meeting := range meetings {
+			if meeting.OrganizedByMe {
+				return meeting, true
+			}
+		}
+		return Meeting{}, false
+	default:
+		return meetings[0], true
+	}
+}
+
+// describeMeeting returns a one-line description of the meeting for choosing between meetings.
+func describeMeeting(meeting Meeting) string {
+	description := meeting.Start.In(meeting.location()).Format(time.Kitchen) + "  " + meeting.Title
+	if meeting.Organizer != "" {
+		description += ", organized by " + meeting.Organizer
+	}
+	if left := meeting.TimeLeft(); left != "" {
+		description += " (in progress, " + left + ")"
+	}
+	return description
+}
+
+// FilterMeetings returns the meetings whose title or organizer fuzzily match the query,
+// best matches first. Every character of the query must appear in order, ignoring case.
+func FilterMeetings(meetings []Meeting, query string) []Meeting {
+	if strings.TrimSpace(query) == "" {
+		return meetings
+	}
+
+	type match struct {
+		meeting Meeting
+		score   int
+	}
+	matches := []match{}
+	for _, meeting := range meetings {
+		if score, ok := fuzzyScore(meeting.Title+" "+meeting.Organizer, query); ok {
+			matches = append(matches, match{meeting, score})
+		}
+	}
+	sort.SliceStable(matches, func(i, j int) bool {
+		return matches[i].score > matches[j].score
+	})
+
+	filtered := make([]Meeting, 0, len(matches))
+	for _, match := range matches {
+		filtered = append(filtered, match.meeting)
+	}
+	return filtered
+}
+
+// fuzzyScore returns how well the text matches the query, which matches if each of its characters appears in
+// the text in order. Consecutive characters and characters starting words score higher.
+func fuzzyScore(text, query string) (int, bool) {
+	textRunes := []rune(strings.ToLower(text))
+	score, last := 0, -2
+	i := 0
+	for _, r := range strings.ToLower(query) {
+		if unicode.IsSpace(r) {
+			continue
+		}
+		for i < len(textRunes) && textRunes[i] != r {
+			i++
+		}
+		if i == len(textRunes) {
+			return 0, false
+		}
+
+		score++
+		if i == last+1 {
+			score += 2
+		}
+		if i == 0 || !unicode.IsLetter(textRunes[i-1]) && !unicode.IsDigit(textRunes[i-1]) {
+			score += 3
+		}
+		last = i
+		i++
+	}
+	return score, true
+}
+
+// PromptMeeting asks the user to pick one of the meetings by number, reading their answer from in.
+// Answering with text instead narrows the list down to the matching meetings.
+// An empty answer returns ErrPickCancelled.
+func PromptMeeting(in io.Reader, out io.Writer, meetings []Meeting) (Meeting, error) {
+	reader := bufio.NewReader(in)
+	choices := meetings
+
+	for {
+		for i, meeting := range choices {
+			fmt.Fprintf(out, "  %d) %s\n", i+1, describeMeeting(meeting))
+		}
+		fmt.Fprintf(out, "Which meeting do you want to join? [1-%d] ", len(choices))
+
+		line, err := reader.ReadString('\n')
+		answer := strings.TrimSpace(line)
+		if answer == "" {
+			if err != nil && err != io.EOF {
+				return Meeting{}, errors.WithStack(err)
+			}
+			return Meeting{}, ErrPickCancelled
+		}
+
+		if n, convErr := strconv.Atoi(answer); convErr == nil {
+			if n >= 1 && n <= len(choices) {
+				return choices[n-1], nil
+			}
+			fmt.Fprintf(out, "Please choose a number between 1 and %d.\n", len(choices))
+		} else if filtered := FilterMeetings(meetings, answer); len(filtered) == 1 {
+			return filtered[0], nil
+		} else if len(filtered) == 0 {
+			fmt.Fprintf(out, "No meetings match %q.\n", answer)
+			choices = meetings
+		} else {
+			choices = filtered
+		}
+
+		if err != nil {
+			return Meeting{}, ErrPickCancelled
+		}
+	}
+}
+
+// Keys the fuzzy picker handles.
+const (
+	keyCtrlC     = 3
+	keyCtrlN     = 14
+	keyCtrlP     = 16
+	keyBackspace = 8
+	keyDelete    = 127
+	keyEscape    = 27
+)
+
+// FuzzyPickMeeting shows an interactive picker in a terminal: typing filters the meetings, the arrow keys
+// or Ctrl-N and Ctrl-P move the selection and Enter picks it. Escape or Ctrl-C returns ErrPickCancelled.
+// The terminal must already be in raw mode, so in delivers each key as it's pressed.
+func FuzzyPickMeeting(in io.Reader, out io.Writer, meetings []Meeting) (Meeting, error) {
+	reader := bufio.NewReader(in)
+	query := []rune{}
+	selected := 0
+	rendered := 0
+
+	for {
+		filtered := FilterMeetings(meetings, string(query))
+		if selected >= len(filtered) {
+			selected = len(filtered) - 1
+		}
+		if selected < 0 {
+			selected = 0
+		}
+		rendered = renderFuzzyPicker(out, filtered, string(query), selected, rendered)
+
+		r, _, err := reader.ReadRune()
+		if err != nil {
+			return Meeting{}, ErrPickCancelled
+		}
+
+		switch r {
+		case '\r', '\n':
+			if len(filtered) > 0 {
+				fmt.Fprint(out, "\r\n")
+				return filtered[selected], nil
+			}
+		case keyCtrlC:
+			fmt.Fprint(out, "\r\n")
+			return Meeting{}, ErrPickCancelled
+		case keyEscape:
+			next, _, err := reader.ReadRune()
+			if err != nil || next != '[' {
+				fmt.Fprint(out, "\r\n")
+				return Meeting{}, ErrPickCancelled
+			}
+			arrow, _, _ := reader.ReadRune()
+			switch arrow {
+			case 'A':
+				selected--
+			case 'B':
+				selected++
+			}
+		case keyCtrlP:
+			selected--
+		case keyCtrlN:
+			selected++
+		case keyBackspace, keyDelete:
+			if len(query) > 0 {
+				query = query[:len(query)-1]
+				selected = 0
+			}
+		default:
+			if unicode.IsPrint(r) {
+				query = append(query, r)
+				selected = 0
+			}
+		}
+	}
+}
+
+// renderFuzzyPicker redraws the picker over the previous rendering, which was the given number of lines,
+// and returns how many lines it drew.
+func renderFuzzyPicker(out io.Writer, meetings []Meeting, query string, selected, previous int) int {
+	var b strings.Builder
+	if previous > 1 {
+		fmt.Fprintf(&b, "\x1b[%dA", previous-1)
+	}
+	b.WriteString("\r\x1b[J")
+
+	for i, meeting := range meetings {
+		marker := "  "
+		if i == selected {
+			marker = "> "
+		}
+		b.WriteString(marker + describeMeeting(meeting) + "\r\n")
+	}
+	if len(meetings) == 0 {
+		b.WriteString("  No matching meetings\r\n")
+	}
+	b.WriteString("Join which meeting? " + query)
+
+	io.WriteString(out, b.String())
+	return strings.Count(b.String(), "\r\n") + 1
+}
@@ -0,0 +1,176 @@
+package zoom
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	calendar "google.golang.org/api/calendar/v3"
+)
+
+func pickerTestMeetings(now time.Time) []Meeting {
+	standup := testEvent("Team Standup", "1", now.Add(-2*time.Minute))
+	standup.Organizer = &calendar.EventOrganizer{DisplayName: "Kevin", Self: false}
+	standup.Attendees = []*calendar.EventAttendee{
+		{Email: "me@jithub.com", Self: true, ResponseStatus: "tentative"},
+		{Email: "kevin@jithub.com", ResponseStatus: "accepted"},
+		{Email: "room@resource.calendar.google.com", Resource: true, ResponseStatus: "accepted"},
+	}
+
+	interview := testEvent("Interview", "2", now.Add(time.Minute))
+	interview.Organizer = &calendar.EventOrganizer{DisplayName: "Me", Self: true}
+
+	allHands := testEvent("All Hands", "3", now.Add(3*time.Minute))
+	allHands.Organizer = &calendar.EventOrganizer{DisplayName: "CEO"}
+	for i := 0; i < 5; i++ {
+		allHands.Attendees = append(allHands.Attendees, &calendar.EventAttendee{Email: "someone@jithub.com", ResponseStatus: "accepted"})
+	}
+	allHands.Attendees = append(allHands.Attendees, &calendar.EventAttendee{Email: "me@jithub.com", Self: true, ResponseStatus: "accepted"})
+
+	later := testEvent("Later", "4", now.Add(time.Hour))
+
+	return []Meeting{NewMeeting(standup), NewMeeting(interview), NewMeeting(allHands), NewMeeting(later)}
+}
+
+func meetingTitles(meetings []Meeting) []string {
+	titles := []string{}
+	for _, meeting := range meetings {
+		titles = append(titles, meeting.Title)
+	}
+	return titles
+}
+
+func TestJoinableMeetings(t *testing.T) {
+	now := time.Now()
+	meetings := pickerTestMeetings(now)
+	// The tentative standup comes after the accepted meetings.
+	assert.Equal(t, []string{"Interview", "All Hands", "Team Standup"}, meetingTitles(JoinableMeetings(meetings, now, DefaultFilter)))
+	assert.Len(t, meetings[0].Attendees, 2, "resources aren't attendees")
+	assert.Equal(t, "tentative", meetings[0].Response)
+}
+
+func TestJoinableMeetings_InProgress(t *testing.T) {
+	now := time.Now()
+	workshop := testEvent("Workshop", "5", now.Add(-20*time.Minute))
+	workshop.End = &calendar.EventDateTime{DateTime: now.Add(100 * time.Minute).Format(googleCalendarDateTimeFormat)}
+	retro := testEvent("Retro", "6", now.Add(-time.Hour))
+	retro.End = &calendar.EventDateTime{DateTime: now.Add(-10 * time.Minute).Format(googleCalendarDateTimeFormat)}
+	meetings := append([]Meeting{NewMeeting(retro), NewMeeting(workshop)}, pickerTestMeetings(now)...)
+
+	// Meetings in progress come after those starting now.
+	assert.Equal(t, []string{"Interview", "All Hands", "Team Standup", "Workshop"}, meetingTitles(JoinableMeetings(meetings, now, DefaultFilter)))
+	assert.Contains(t, describeMeeting(NewMeeting(workshop)), "Workshop (in progress, 1 hr 40 min left)")
+}
+
+func TestPickPolicy(t *testing.T) {
+	now := time.Now()
+	joinable := JoinableMeetings(pickerTestMeetings(now), now, DefaultFilter)
+
+	testCases := []struct {
+		policy PickPolicy
+		title  string
+	}{
+		{PickFirst, "Interview"},
+		{PickMostAttendees, "All Hands"},
+		{PickAccepted, "Interview"},
+		{PickOrganizedByMe, "Interview"},
+	}
+	for _, testCase := range testCases {
+		t.Run(string(testCase.policy), func(t *testing.T) {
+			meeting, ok := testCase.policy.Pick(joinable)
+			require.True(t, ok)
+			assert.Equal(t, testCase.title, meeting.Title)
+		})
+	}
+
+	_, ok := PickOrganizedByMe.Pick(joinable[1:])
+	assert.False(t, ok)
+	_, ok = PickFirst.Pick(nil)
+	assert.False(t, ok)
+}
+
+func TestParsePickPolicy(t *testing.T) {
+	policy, err := ParsePickPolicy("")
+	require.NoError(t, err)
+	assert.Equal(t, PickFirst, policy)
+
+	policy, err = ParsePickPolicy("most-attendees")
+	require.NoError(t, err)
+	assert.Equal(t, PickMostAttendees, policy)
+
+	_, err = ParsePickPolicy("random")
+	assert.EqualError(t, err, `unknown pick policy "random", expected one of first, most-attendees, accepted, organized-by-me`)
+}
+
+func TestFilterMeetings(t *testing.T) {
+	meetings := pickerTestMeetings(time.Now())
+
+	assert.Equal(t, []string{"Team Standup", "Interview", "All Hands", "Later"}, meetingTitles(FilterMeetings(meetings, "")))
+	assert.Equal(t, []string{"Team Standup"}, meetingTitles(FilterMeetings(meetings, "stnd")))
+	assert.Equal(t, []string{"All Hands"}, meetingTitles(FilterMeetings(meetings, "ceo")))
+	assert.Equal(t, []string{"All Hands", "Team Standup"}, meetingTitles(FilterMeetings(meetings, "an")))
+	assert.Empty(t, FilterMeetings(meetings, "xyz"))
+}
+
+func TestPromptMeeting(t *testing.T) {
+	now := time.Now()
+	joinable := JoinableMeetings(pickerTestMeetings(now), now, DefaultFilter)
+
+	testCases := []struct {
+		name  string
+		input string
+		title string
+		err   error
+	}{
+		{"number", "2\n", "All Hands", nil},
+		{"out of range then number", "7\n3\n", "Team Standup", nil},
+		{"unique filter", "hands\n", "All Hands", nil},
+		{"ambiguous filter then number", "an\n2\n", "Team Standup", nil},
+		{"empty", "\n", "", ErrPickCancelled},
+		{"eof", "", "", ErrPickCancelled},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			var out bytes.Buffer
+			meeting, err := PromptMeeting(strings.NewReader(testCase.input), &out, joinable)
+			assert.Equal(t, testCase.err, err)
+			assert.Equal(t, testCase.title, meeting.Title)
+			assert.Contains(t, out.String(), "1) ")
+			assert.Contains(t, out.String(), "Team Standup, organized by Kevin")
+		})
+	}
+}
+
+func TestFuzzyPickMeeting(t *testing.T) {
+	now := time.Now()
+	joinable := JoinableMeetings(pickerTestMeetings(now), now, DefaultFilter)
+
+	testCases := []struct {
+		name  string
+		input string
+		title string
+		err   error
+	}{
+		{"enter picks first", "\r", "Interview", nil},
+		{"down arrow", "\x1b[B\x1b[B\r", "Team Standup", nil},
+		{"up arrow stops at top", "\x1b[A\r", "Interview", nil},
+		{"ctrl-n and ctrl-p", "\x0e\x0e\x10\r", "All Hands", nil},
+		{"typing filters", "intv\r", "Interview", nil},
+		{"backspace", "intx\x7f\x7fv\r", "Interview", nil},
+		{"no matches ignores enter", "xyz\r\x7f\x7f\x7f\r", "Interview", nil},
+		{"ctrl-c", "\x03", "", ErrPickCancelled},
+		{"escape", "\x1bq", "", ErrPickCancelled},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			var out bytes.Buffer
+			meeting, err := FuzzyPickMeeting(strings.NewReader(testCase.input), &out, joinable)
+			assert.Equal(t, testCase.err, err)
+			assert.Equal(t, testCase.title, meeting.Title)
+			assert.Contains(t, out.String(), "> ")
+		})
+	}
+}
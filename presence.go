@@ -0,0 +1,337 @@
+package zoom
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"os"
+	"os/exec"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	// DefaultPresenceSoon is how long before a meeting starts the presence becomes meeting-soon.
+	DefaultPresenceSoon = 5 * time.Minute
+	// DefaultHookTimeout is how long a presence or meeting hook may run.
+	DefaultHookTimeout = 10 * time.Second
+
+	// presenceLookback is how far back the PresenceEngine looks for meetings which may still be in progress.
+	presenceLookback = 12 * time.Hour
+)
+
+// PresenceState is whether the user is free, about to be in a meeting or in one.
+type PresenceState string
+
+// The presence states.
+const (
+	PresenceFree        PresenceState = "free"
+	PresenceMeetingSoon PresenceState = "meeting-soon"
+	PresenceInMeeting   PresenceState = "in-meeting"
+)
+
+// Presence is the user's presence at a point in time.
+type Presence struct {
+	State PresenceState `json:"state"`
+	// Meeting is the meeting the user is in or about to be in. It's nil when they're free.
+	Meeting *Meeting `json:"meeting,omitempty"`
+}
+
+// ComputePresence returns the presence at now given the meetings: in-meeting if one is in progress,
+// meeting-soon if one starts within soon, otherwise free.
+func ComputePresence(meetings []Meeting, now time.Time, soon time.Duration) Presence {
+	var next *Meeting
+	for i := range meetings {
+		meeting := meetings[i]
+		if meeting.Start.IsZero() {
+			continue
+		}
+		if !now.Before(meeting.Start) && now.Before(meetingEnd(meeting)) {
+			return Presence{State: PresenceInMeeting, Meeting: &meeting}
+		}
+		if now.Before(meeting.Start) && meeting.Start.Sub(now) <= soon && (next == nil || meeting.Start.Before(next.Start)) {
+			next = &meeting
+		}
+	}
+
+	if next != nil {
+		return Presence{State: PresenceMeetingSoon, Meeting: next}
+	}
+	return Presence{State: PresenceFree}
+}
+
+// PresenceChange is a transition from one presence to another. It's the JSON payload sent to hooks.
+type PresenceChange struct {
+	Presence
+	// Previous is the state before the change. It's empty for the first presence computed.
+	Previous  PresenceState `json:"previous,omitempty"`
+	ChangedAt time.Time     `json:"changed_at"`
+}
+
+// PresenceHook is notified about presence changes.
+type PresenceHook interface {
+	Fire(ctx context.Context, change PresenceChange) error
+}
+
+// CommandHook runs a shell command on each presence change. The change is written to the command's standard input
+// as JSON, and the state, previous state, meeting title and URL are set in the ZOOM_PRESENCE,
+// ZOOM_PREVIOUS_PRESENCE, ZOOM_MEETING_TITLE and ZOOM_MEETING_URL environment variables.
+type CommandHook struct {
+	Command string
+	// Timeout defaults to DefaultHookTimeout.
+	Timeout time.Duration
+}
+
+// Fire runs the command.
+func (h *CommandHook) Fire(ctx context.Context, change PresenceChange) error {
+	payload, err := json.Marshal(change)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, hookTimeout(h.Timeout))
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, "sh", "-c", h.Command)
+	// Children left running, like a backgrounded app, mustn't keep the presence loop waiting, like MeetingHook.
+	cmd.WaitDelay = time.Second
+	cmd.Stdin = bytes.NewReader(payload)
+	cmd.Env = append(os.Environ(),
+		"ZOOM_PRESENCE="+string(change.State),
+		"ZOOM_PREVIOUS_PRESENCE="+string(change.Previous),
+	)
+	if change.Meeting != nil {
+		cmd.Env = append(cmd.Env, "ZOOM_MEETING_TITLE="+change.Meeting.Title, "ZOOM_MEETING_URL="+change.Meeting.URL)
+	}
+
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return errors.Wrapf(err, "command %q failed: %s", h.Command, bytes.TrimSpace(output))
+	}
+	return nil
+}
+
+// WebhookSignatureHeader is the header a WebhookHook signs its payloads in, as "sha256=" followed by the
+// hex-encoded HMAC-SHA256 of the request body keyed with the secret.
+const WebhookSignatureHeader = "X-Zoom-Signature-256"
+
+// WebhookHook posts each presence change as JSON to a URL.
+type WebhookHook struct {
+	URL string
+	// Secret signs the payloads in the WebhookSignatureHeader. If it's empty, payloads aren't signed.
+	Secret string
+	// Client defaults to an http.Client with a DefaultHookTimeout timeout.
+	Client *http.Client
+}
+
+// Fire posts the change to the webhook.
+func (h *WebhookHook) Fire(ctx context.Context, change PresenceChange) error {
+	payload, err := json.Marshal(change)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, h.URL, bytes.NewReader(payload))
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "zoom-go")
+	if h.Secret != "" {
+		req.Header.Set(WebhookSignatureHeader, SignWebhookPayload(h.Secret, payload))
+	}
+
+	client := h.Client
+	if client == nil {
+		client = &http.Client{Timeout: DefaultHookTimeout}
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	defer resp.Body.Close()
+	ioutil.ReadAll(resp.Body)
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return errors.Errorf("webhook %s responded %s", h.URL, resp.Status)
+	}
+	return nil
+}
+
+// SignWebhookPayload returns the WebhookSignatureHeader value for the payload.
+func SignWebhookPayload(secret string, payload []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(payload)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// StateFileHook writes the latest presence change as JSON to a file other tools can read.
+// The file is replaced atomically, so readers never see a partially written file.
+type StateFileHook struct {
+	Path string
+}
+
+// Fire writes the change to the state file.
+func (h *StateFileHook) Fire(ctx context.Context, change PresenceChange) error {
+	return writeJSONAtomic(h.Path, change)
+}
+
+func hookTimeout(timeout time.Duration) time.Duration {
+	if timeout <= 0 {
+		return DefaultHookTimeout
+	}
+	return timeout
+}
+
+// PresenceEngine runs continuously, computing the user's presence from their calendar
+// and firing hooks whenever it changes, including when one meeting follows straight on from another.
+type PresenceEngine struct {
+	// Source provides the meetings.
+	Source EventSource
+	// Hooks are fired in order on each change.
+	Hooks []PresenceHook
+	// Soon is how long before a meeting starts the presence becomes meeting-soon. It defaults to DefaultPresenceSoon.
+	Soon time.Duration
+	// PollInterval is how often to check the calendar for changes. It defaults to DefaultPollInterval.
+	PollInterval time.Duration
+	// Clock defaults to SystemClock.
+	Clock Clock
+	// Logger logs what the PresenceEngine does. It defaults to discarding the logs.
+	Logger *log.Logger
+	// Filter decides which meetings count. Meetings it doesn't join automatically, like all-day events,
+	// don't make the user busy.
+	Filter Filter
+
+	meetings []Meeting
+	lastPoll time.Time
+	current  *Presence
+}
+
+// Run computes the presence until the context is done.
+func (e *PresenceEngine) Run(ctx context.Context) error {
+	for {
+		wait := e.tick(ctx)
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-e.clock().After(wait):
+		}
+	}
+}
+
+// tick polls the calendar if it's due, fires the hooks if the presence changed,
+// and returns how long to sleep until the next tick.
+func (e *PresenceEngine) tick(ctx context.Context) time.Duration {
+	now := e.clock().Now()
+
+	if e.lastPoll.IsZero() || now.Sub(e.lastPoll) >= e.pollInterval() || now.Before(e.lastPoll) {
+		e.poll(now)
+	}
+
+	presence := ComputePresence(e.meetings, now, e.soon())
+	if e.current == nil || presenceChanged(*e.current, presence) {
+		change := PresenceChange{Presence: presence, ChangedAt: now}
+		if e.current != nil {
+			change.Previous = e.current.State
+		}
+		e.current = &presence
+		e.fire(ctx, change)
+	}
+
+	next := e.lastPoll.Add(e.pollInterval()).Sub(now)
+	for _, meeting := range e.meetings {
+		for _, transition := range []time.Time{meeting.Start.Add(-e.soon()), meeting.Start, meetingEnd(meeting)} {
+			if transition.After(now) && transition.Sub(now) < next {
+				next = transition.Sub(now)
+			}
+		}
+	}
+
+	if next > watchMaxSleep {
+		next = watchMaxSleep
+	}
+	if next < 0 {
+		next = 0
+	}
+	return next
+}
+
+// presenceChanged returns true if the state or the meeting changed.
+func presenceChanged(old, presence Presence) bool {
+	if old.State != presence.State {
+		return true
+	}
+	if old.Meeting == nil || presence.Meeting == nil {
+		return old.Meeting != presence.Meeting
+	}
+	return old.Meeting.ID != presence.Meeting.ID
+}
+
+// fire fires each hook, logging failures so one broken hook doesn't stop the others.
+func (e *PresenceEngine) fire(ctx context.Context, change PresenceChange) {
+	if change.Meeting != nil {
+		e.logf("Presence is %s: %q", change.State, change.Meeting.Title)
+	} else {
+		e.logf("Presence is %s", change.State)
+	}
+
+	for _, hook := range e.Hooks {
+		if err := hook.Fire(ctx, change); err != nil {
+			e.logf("Presence hook failed: %v", err)
+		}
+	}
+}
+
+// poll refreshes the meetings from the source. If the source fails, the previous meetings are kept.
+func (e *PresenceEngine) poll(now time.Time) {
+	e.lastPoll = now
+
+	events, err := e.Source.EventsBetween(now.Add(-presenceLookback), now.Add(watchHorizon))
+	if err != nil && !errors.Is(err, ErrNoMeetings) {
+		e.logf("Unable to check calendar: %v", err)
+		return
+	}
+
+	meetings := make([]Meeting, 0, len(events))
+	for _, event := range events {
+		if e.Filter.AutoJoins(event) {
+			meetings = append(meetings, NewMeeting(event))
+		}
+	}
+	e.meetings = meetings
+}
+
+func (e *PresenceEngine) clock() Clock {
+	if e.Clock == nil {
+		return SystemClock
+	}
+	return e.Clock
+}
+
+func (e *PresenceEngine) soon() time.Duration {
+	if e.Soon <= 0 {
+		return DefaultPresenceSoon
+	}
+	return e.Soon
+}
+
+func (e *PresenceEngine) pollInterval() time.Duration {
+	if e.PollInterval <= 0 {
+		return DefaultPollInterval
+	}
+	return e.PollInterval
+}
+
+func (e *PresenceEngine) logf(format string, args ...interface{}) {
+	if e.Logger == nil {
+		e.Logger = log.New(ioutil.Discard, "", 0)
+	}
+	e.Logger.Printf(format, args...)
+}
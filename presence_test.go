@@ -0,0 +1,199 @@
+package zoom
+
+import (
+	"context"
+	"crypto/hmac"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// recordingHook records the presence changes it's fired with.
+type recordingHook struct {
+	changes []PresenceChange
+}
+
+func (h *recordingHook) Fire(ctx context.Context, change PresenceChange) error {
+	h.changes = append(h.changes, change)
+	return nil
+}
+
+func TestComputePresence(t *testing.T) {
+	now := time.Date(2021, 3, 1, 10, 0, 0, 0, time.UTC)
+	standup := NewMeeting(testEvent("standup", "standup", now.Add(-10*time.Minute), withEnd(now.Add(5*time.Minute))))
+	retro := NewMeeting(testEvent("retro", "retro", now.Add(3*time.Minute), withEnd(now.Add(time.Hour))))
+	noEnd := NewMeeting(testEvent("1:1", "1", now.Add(-20*time.Minute)))
+
+	testCases := []struct {
+		name     string
+		meetings []Meeting
+		state    PresenceState
+		meeting  string
+	}{
+		{"no meetings", nil, PresenceFree, ""},
+		{"in progress", []Meeting{standup}, PresenceInMeeting, "standup"},
+		{"starting soon", []Meeting{retro}, PresenceMeetingSoon, "retro"},
+		{"in progress beats starting soon", []Meeting{retro, standup}, PresenceInMeeting, "standup"},
+		{"no end time lasts 30 minutes", []Meeting{noEnd}, PresenceInMeeting, "1:1"},
+		{"ended", []Meeting{NewMeeting(testEvent("done", "done", now.Add(-time.Hour), withEnd(now)))}, PresenceFree, ""},
+		{"later", []Meeting{NewMeeting(testEvent("later", "later", now.Add(time.Hour), withEnd(now.Add(2*time.Hour))))}, PresenceFree, ""},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			presence := ComputePresence(testCase.meetings, now, 5*time.Minute)
+			assert.Equal(t, testCase.state, presence.State)
+			if testCase.meeting == "" {
+				assert.Nil(t, presence.Meeting)
+			} else {
+				require.NotNil(t, presence.Meeting)
+				assert.Equal(t, testCase.meeting, presence.Meeting.Title)
+			}
+		})
+	}
+}
+
+func TestPresenceEngine_Transitions(t *testing.T) {
+	start := time.Now().Truncate(time.Second)
+	clock := &fakeClock{now: start}
+	source := &fakeSource{}
+	source.set(
+		testEvent("standup", "standup", start.Add(10*time.Minute), withEnd(start.Add(20*time.Minute))),
+		testEvent("retro", "retro", start.Add(20*time.Minute), withEnd(start.Add(30*time.Minute))),
+	)
+	hook := &recordingHook{}
+	engine := &PresenceEngine{Source: source, Hooks: []PresenceHook{hook}, Clock: clock, PollInterval: time.Hour}
+
+	wait := engine.tick(context.Background())
+	assert.Equal(t, watchMaxSleep, wait)
+	require.Len(t, hook.changes, 1)
+	assert.Equal(t, PresenceFree, hook.changes[0].State)
+	assert.Empty(t, hook.changes[0].Previous)
+
+	// Nothing changes until the meeting is about to start.
+	clock.Advance(4*time.Minute + 50*time.Second)
+	assert.Equal(t, 10*time.Second, engine.tick(context.Background()))
+	assert.Len(t, hook.changes, 1)
+
+	clock.Advance(10 * time.Second)
+	engine.tick(context.Background())
+	require.Len(t, hook.changes, 2)
+	assert.Equal(t, PresenceMeetingSoon, hook.changes[1].State)
+	assert.Equal(t, PresenceFree, hook.changes[1].Previous)
+
+	clock.Advance(5 * time.Minute)
+	engine.tick(context.Background())
+	require.Len(t, hook.changes, 3)
+	assert.Equal(t, PresenceInMeeting, hook.changes[2].State)
+	assert.Equal(t, "standup", hook.changes[2].Meeting.Title)
+
+	// Back-to-back meetings change the meeting without changing the state.
+	clock.Advance(10 * time.Minute)
+	engine.tick(context.Background())
+	require.Len(t, hook.changes, 4)
+	assert.Equal(t, PresenceInMeeting, hook.changes[3].State)
+	assert.Equal(t, PresenceInMeeting, hook.changes[3].Previous)
+	assert.Equal(t, "retro", hook.changes[3].Meeting.Title)
+
+	clock.Advance(10 * time.Minute)
+	engine.tick(context.Background())
+	require.Len(t, hook.changes, 5)
+	assert.Equal(t, PresenceFree, hook.changes[4].State)
+	assert.Nil(t, hook.changes[4].Meeting)
+}
+
+func TestWebhookHook(t *testing.T) {
+	received := make(chan *http.Request, 1)
+	var body []byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ = ioutil.ReadAll(r.Body)
+		received <- r
+	}))
+	defer server.Close()
+
+	now := time.Now().UTC().Truncate(time.Second)
+	meeting := NewMeeting(testEvent("standup", "standup", now, withEnd(now.Add(time.Hour))))
+	change := PresenceChange{Presence: Presence{State: PresenceInMeeting, Meeting: &meeting}, Previous: PresenceMeetingSoon, ChangedAt: now}
+
+	hook := &WebhookHook{URL: server.URL, Secret: "s3cret"}
+	require.NoError(t, hook.Fire(context.Background(), change))
+
+	r := <-received
+	assert.Equal(t, http.MethodPost, r.Method)
+	assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+	signature := r.Header.Get(WebhookSignatureHeader)
+	assert.True(t, strings.HasPrefix(signature, "sha256="))
+	assert.True(t, hmac.Equal([]byte(SignWebhookPayload("s3cret", body)), []byte(signature)))
+	assert.NotEqual(t, SignWebhookPayload("wrong", body), signature)
+
+	payload := map[string]interface{}{}
+	require.NoError(t, json.Unmarshal(body, &payload))
+	assert.Equal(t, "in-meeting", payload["state"])
+	assert.Equal(t, "meeting-soon", payload["previous"])
+	assert.Equal(t, "standup", payload["meeting"].(map[string]interface{})["title"])
+}
+
+func TestWebhookHook_Failure(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Empty(t, r.Header.Get(WebhookSignatureHeader))
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	hook := &WebhookHook{URL: server.URL}
+	err := hook.Fire(context.Background(), PresenceChange{Presence: Presence{State: PresenceFree}})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "500")
+}
+
+func TestStateFileHook(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "presence", "state.json")
+	hook := &StateFileHook{Path: path}
+
+	require.NoError(t, hook.Fire(context.Background(), PresenceChange{Presence: Presence{State: PresenceMeetingSoon}, Previous: PresenceFree}))
+	require.NoError(t, hook.Fire(context.Background(), PresenceChange{Presence: Presence{State: PresenceFree}, Previous: PresenceMeetingSoon}))
+
+	b, err := ioutil.ReadFile(path)
+	require.NoError(t, err)
+	change := PresenceChange{}
+	require.NoError(t, json.Unmarshal(b, &change))
+	assert.Equal(t, PresenceFree, change.State)
+	assert.Equal(t, PresenceMeetingSoon, change.Previous)
+}
+
+func TestCommandHook(t *testing.T) {
+	dir := t.TempDir()
+	now := time.Now()
+	meeting := NewMeeting(testEvent("standup", "standup", now, withEnd(now.Add(time.Hour))))
+	change := PresenceChange{Presence: Presence{State: PresenceInMeeting, Meeting: &meeting}, Previous: PresenceFree}
+
+	hook := &CommandHook{Command: `echo "$ZOOM_PREVIOUS_PRESENCE $ZOOM_PRESENCE $ZOOM_MEETING_TITLE" > env.txt && cat > payload.json`}
+	hook.Command = "cd " + dir + " && " + hook.Command
+	require.NoError(t, hook.Fire(context.Background(), change))
+
+	env, err := ioutil.ReadFile(filepath.Join(dir, "env.txt"))
+	require.NoError(t, err)
+	assert.Equal(t, "free in-meeting standup\n", string(env))
+
+	payload, err := ioutil.ReadFile(filepath.Join(dir, "payload.json"))
+	require.NoError(t, err)
+	assert.Contains(t, string(payload), `"state":"in-meeting"`)
+
+	failing := &CommandHook{Command: "echo oops >&2; exit 3", Timeout: time.Second}
+	err = failing.Fire(context.Background(), change)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "oops")
+
+	// A command which leaves a child running with its output open doesn't block until the child exits.
+	started := time.Now()
+	background := &CommandHook{Command: "sleep 5 & echo started", Timeout: 50 * time.Millisecond}
+	background.Fire(context.Background(), change)
+	assert.WithinDuration(t, started, time.Now(), 3*time.Second)
+}
@@ -0,0 +1,378 @@
+package zoom
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// ReportFormats lists the formats supported by WriteReport.
+var ReportFormats = []string{"table", "csv", "json"}
+
+// WorkingHours are the times of day free time is counted in. The zero value is DefaultWorkingHours.
+type WorkingHours struct {
+	// Start and End are offsets from midnight.
+	Start, End time.Duration
+}
+
+// DefaultWorkingHours are 09:00 to 17:00.
+var DefaultWorkingHours = WorkingHours{Start: 9 * time.Hour, End: 17 * time.Hour}
+
+// ParseWorkingHours parses working hours in the form "09:00-17:00". An empty string means DefaultWorkingHours.
+func ParseWorkingHours(s string) (WorkingHours, error) {
+	if strings.TrimSpace(s) == "" {
+		return DefaultWorkingHours, nil
+	}
+	start, end, ok := parseClockRange(s)
+	if !ok || end <= start {
+		return WorkingHours{}, errors.Errorf("invalid working hours %q, expected e.g. 09:00-17:00", s)
+	}
+	return WorkingHours{Start: start, End: end}, nil
+}
+
+func (h WorkingHours) String() string {
+	return formatClockRange(h.Start, h.End)
+}
+
+// Load is how many meetings there are in a group and how long they take.
+type Load struct {
+	Name     string        `json:"name"`
+	Meetings int           `json:"meetings"`
+	Time     time.Duration `json:"-"`
+	// Minutes is Time in minutes, for JSON.
+	Minutes int `json:"minutes"`
+}
+
+// DayLoad is the meetings on a day, along with the longest time without any during working hours.
+type DayLoad struct {
+	Load
+	Date time.Time `json:"date"`
+	// LongestFree is the longest block of working hours without meetings, starting at LongestFreeStart.
+	LongestFree      time.Duration `json:"-"`
+	LongestFreeStart time.Time     `json:"longest_free_start,omitempty"`
+	// LongestFreeMinutes is LongestFree in minutes, for JSON.
+	LongestFreeMinutes int `json:"longest_free_minutes"`
+}
+
+// Report is how much time meetings take over a range of days, broken down in several ways.
+// Meetings at the same time are counted once in the totals and each day's time, but separately everywhere else.
+type Report struct {
+	From         time.Time    `json:"from"`
+	To           time.Time    `json:"to"`
+	WorkingHours WorkingHours `json:"-"`
+	Total        Load         `json:"total"`
+	Days         []DayLoad    `json:"days"`
+	Organizers   []Load       `json:"organizers"`
+	Series       []Load       `json:"series"`
+	Providers    []Load       `json:"providers"`
+	Attendees    []Load       `json:"attendees"`
+}
+
+// One-off meetings, which aren't part of a series, are grouped together under this name.
+const oneOffSeries = "One-off meetings"
+
+// attendeeBuckets group meetings by how many people are invited.
+var attendeeBuckets = []struct {
+	name string
+	max  int
+}{
+	{"Just you", 1},
+	{"1:1", 2},
+	{"3-5 people", 5},
+	{"6-10 people", 10},
+	{"11+ people", int(^uint(0) >> 1)},
+}
+
+// NewReport reports on the meetings which start between from and to, each day of which starts at midnight in
+// from's time zone. All-day events and meetings without a start time aren't counted, and meetings without an end
+// time are assumed to last 30 minutes.
+func NewReport(meetings []Meeting, from, to time.Time, hours WorkingHours) Report {
+	if hours == (WorkingHours{}) {
+		hours = DefaultWorkingHours
+	}
+	loc := from.Location()
+	report := Report{From: from, To: to, WorkingHours: hours, Total: Load{Name: "Total"}}
+
+	counted := []Meeting{}
+	for _, meeting := range meetings {
+		if meeting.Start.IsZero() || meeting.AllDay || meeting.Start.Before(from) || !meeting.Start.Before(to) {
+			continue
+		}
+		counted = append(counted, meeting)
+	}
+
+	organizers, series, providers, attendees := newLoads(), newLoads(), newLoads(), newLoads()
+	// Every bucket is listed, even if no meetings fall in it.
+	for _, bucket := range attendeeBuckets {
+		attendees.get(bucket.name, bucket.name)
+	}
+	for _, meeting := range counted {
+		length := meetingEnd(meeting).Sub(meeting.Start)
+
+		organizer := meeting.Organizer
+		if meeting.OrganizedByMe {
+			organizer = "You"
+		} else if organizer == "" {
+			organizer = "Unknown"
+		}
+		organizers.add(organizer, length)
+
+		if meeting.SeriesID == "" {
+			series.add(oneOffSeries, length)
+		} else {
+			series.addKeyed(meeting.SeriesID, meeting.Title, length)
+		}
+
+		provider := meeting.Provider
+		if provider == "" {
+			provider = "Unknown"
+		}
+		providers.add(provider, length)
+
+		for _, bucket := range attendeeBuckets {
+			if len(meeting.Attendees) <= bucket.max {
+				attendees.add(bucket.name, length)
+				break
+			}
+		}
+	}
+
+	for day := StartOfDay(from.In(loc)); day.Before(to); day = day.AddDate(0, 0, 1) {
+		end := day.AddDate(0, 0, 1)
+		load := DayLoad{Load: Load{Name: day.Format("Mon Jan 2")}, Date: day}
+		busy := [][2]time.Time{}
+		for _, meeting := range counted {
+			if !meeting.Start.Before(day) && meeting.Start.Before(end) {
+				load.Meetings++
+				busy = append(busy, [2]time.Time{meeting.Start, meetingEnd(meeting)})
+			}
+		}
+		load.Time = busyTime(busy)
+		load.Minutes = int(load.Time.Minutes())
+		load.LongestFreeStart, load.LongestFree = longestFree(busy, day.Add(hours.Start), day.Add(hours.End))
+		load.LongestFreeMinutes = int(load.LongestFree.Minutes())
+		report.Days = append(report.Days, load)
+
+		report.Total.Meetings += load.Meetings
+		report.Total.Time += load.Time
+	}
+	report.Total.Minutes = int(report.Total.Time.Minutes())
+
+	report.Organizers = organizers.sorted()
+	report.Series = series.sorted()
+	report.Providers = providers.sorted()
+	report.Attendees = attendees.list()
+	return report
+}
+
+// loads accumulates Loads by key, remembering the order keys were first seen.
+type loads struct {
+	keys  []string
+	loads map[string]*Load
+}
+
+func newLoads() *loads {
+	return &loads{loads: map[string]*Load{}}
+}
+
+// get returns the load with the key, adding an empty one with the name if there isn't one.
+func (l *loads) get(key, name string) *Load {
+	load, ok := l.loads[key]
+	if !ok {
+		load = &Load{Name: name}
+		l.loads[key] = load
+		l.keys = append(l.keys, key)
+	}
+	return load
+}
+
+func (l *loads) add(name string, length time.Duration) {
+	l.addKeyed(name, name, length)
+}
+
+// addKeyed adds a meeting to the load with the key, named after the first meeting added to it.
+func (l *loads) addKeyed(key, name string, length time.Duration) {
+	load := l.get(key, name)
+	load.Meetings++
+	load.Time += length
+	load.Minutes = int(load.Time.Minutes())
+}
+
+// list returns the loads in the order they were first seen.
+func (l *loads) list() []Load {
+	list := make([]Load, 0, len(l.keys))
+	for _, key := range l.keys {
+		list = append(list, *l.loads[key])
+	}
+	return list
+}
+
+// sorted returns the loads, most time first.
+func (l *loads) sorted() []Load {
+	list := l.list()
+	sort.SliceStable(list, func(i, j int) bool {
+		return list[i].Time > list[j].Time
+	})
+	return list
+}
+
+// busyTime returns how much time the intervals cover, counting overlapping time once.
+func busyTime(intervals [][2]time.Time) time.Duration {
+	intervals = mergeIntervals(intervals)
+	total := time.Duration(0)
+	for _, interval := range intervals {
+		total += interval[1].Sub(interval[0])
+	}
+	return total
+}
+
+// longestFree returns the longest time between start and end the intervals don't cover.
+func longestFree(intervals [][2]time.Time, start, end time.Time) (time.Time, time.Duration) {
+	longestStart, longest := time.Time{}, time.Duration(0)
+	consider := func(from, to time.Time) {
+		if from.Before(start) {
+			from = start
+		}
+		if to.After(end) {
+			to = end
+		}
+		if free := to.Sub(from); free > longest {
+			longestStart, longest = from, free
+		}
+	}
+
+	free := start
+	for _, interval := range mergeIntervals(intervals) {
+		consider(free, interval[0])
+		if interval[1].After(free) {
+			free = interval[1]
+		}
+	}
+	consider(free, end)
+	return longestStart.In(start.Location()), longest
+}
+
+// mergeIntervals sorts the intervals and merges those which overlap.
+func mergeIntervals(intervals [][2]time.Time) [][2]time.Time {
+	sorted := append([][2]time.Time{}, intervals...)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i][0].Before(sorted[j][0])
+	})
+
+	merged := [][2]time.Time{}
+	for _, interval := range sorted {
+		if last := len(merged) - 1; last >= 0 && !interval[0].After(merged[last][1]) {
+			if interval[1].After(merged[last][1]) {
+				merged[last][1] = interval[1]
+			}
+			continue
+		}
+		merged = append(merged, interval)
+	}
+	return merged
+}
+
+// WriteReport writes the report to w in the given format, one of ReportFormats.
+func WriteReport(w io.Writer, format string, report Report) error {
+	switch format {
+	case "table", "text":
+		return writeReportTable(w, report)
+	case "csv":
+		return writeReportCSV(w, report)
+	case "json":
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return errors.WithStack(encoder.Encode(report))
+	default:
+		return errors.Errorf("unknown report format %q, expected one of: %s", format, strings.Join(ReportFormats, ", "))
+	}
+}
+
+// writeReportTable writes the report as aligned tables.
+func writeReportTable(w io.Writer, report Report) error {
+	output := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+
+	fmt.Fprintf(output, "%s to %s: %s, %s in meetings\n\n",
+		report.From.Format("Mon Jan 2"), report.To.Add(-time.Nanosecond).Format("Mon Jan 2"),
+		pluralize(report.Total.Meetings, "meeting"), formatLoadTime(report.Total.Time))
+
+	fmt.Fprintf(output, "Day\tMeetings\tTime\tLongest free block (%s)\n", report.WorkingHours)
+	for _, day := range report.Days {
+		free := "none"
+		if day.LongestFree > 0 {
+			free = formatDuration(day.LongestFree) + " from " + day.LongestFreeStart.Format("15:04")
+		}
+		fmt.Fprintf(output, "%s\t%d\t%s\t%s\n", day.Name, day.Meetings, formatLoadTime(day.Time), free)
+	}
+
+	for _, section := range []struct {
+		title string
+		loads []Load
+	}{
+		{"Organizer", report.Organizers},
+		{"Series", report.Series},
+		{"Provider", report.Providers},
+		{"Attendees", report.Attendees},
+	} {
+		fmt.Fprintf(output, "\n%s\tMeetings\tTime\n", section.title)
+		for _, load := range section.loads {
+			fmt.Fprintf(output, "%s\t%d\t%s\n", load.Name, load.Meetings, formatLoadTime(load.Time))
+		}
+	}
+
+	return errors.WithStack(output.Flush())
+}
+
+// writeReportCSV writes the report as CSV, with a row for each group and the kind of group in the first column.
+func writeReportCSV(w io.Writer, report Report) error {
+	output := csv.NewWriter(w)
+	write := func(record ...string) {
+		output.Write(record)
+	}
+
+	write("group", "name", "meetings", "minutes", "longest_free_minutes")
+	write("total", report.Total.Name, strconv.Itoa(report.Total.Meetings), strconv.Itoa(report.Total.Minutes), "")
+	for _, day := range report.Days {
+		write("day", day.Date.Format("2006-01-02"), strconv.Itoa(day.Meetings), strconv.Itoa(day.Minutes), strconv.Itoa(day.LongestFreeMinutes))
+	}
+	for _, section := range []struct {
+		group string
+		loads []Load
+	}{
+		{"organizer", report.Organizers},
+		{"series", report.Series},
+		{"provider", report.Providers},
+		{"attendees", report.Attendees},
+	} {
+		for _, load := range section.loads {
+			write(section.group, load.Name, strconv.Itoa(load.Meetings), strconv.Itoa(load.Minutes), "")
+		}
+	}
+
+	output.Flush()
+	return errors.WithStack(output.Error())
+}
+
+// formatLoadTime formats time spent in meetings, which may be none.
+func formatLoadTime(d time.Duration) string {
+	if d <= 0 {
+		return "-"
+	}
+	return formatHoursMinutes(d)
+}
+
+// pluralize returns the count with the noun, pluralized if needed, e.g. "3 meetings".
+func pluralize(count int, noun string) string {
+	if count == 1 {
+		return "1 " + noun
+	}
+	return fmt.Sprintf("%d %ss", count, noun)
+}
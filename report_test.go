@@ -0,0 +1,137 @@
+package zoom
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	calendar "google.golang.org/api/calendar/v3"
+)
+
+func reportTestMeetings(monday time.Time) []Meeting {
+	standup := func(day int) Meeting {
+		meeting := NewMeeting(testEvent("standup", "standup", monday.AddDate(0, 0, day).Add(9*time.Hour+30*time.Minute), lasting(15*time.Minute)))
+		meeting.SeriesID = "standup-series"
+		meeting.Organizer = "Kevin Jithub"
+		meeting.Attendees = make([]Attendee, 6)
+		return meeting
+	}
+
+	oneOnOne := NewMeeting(testEvent("1:1", "1:1", monday.Add(11*time.Hour), lasting(30*time.Minute)))
+	oneOnOne.OrganizedByMe = true
+	oneOnOne.Attendees = make([]Attendee, 2)
+
+	// The review overlaps the 1:1, so Monday is busy from 11:00 to 12:15.
+	review := NewMeeting(testEvent("review", "review", monday.Add(11*time.Hour+15*time.Minute), lasting(time.Hour)))
+	review.Organizer = "Dana Scully"
+	review.Provider = "zoom.us"
+	review.Attendees = make([]Attendee, 12)
+
+	offsite := NewMeeting(testEvent("offsite", "offsite", monday.AddDate(0, 0, 2), allDay()))
+	nextWeek := NewMeeting(testEvent("next week", "next week", monday.AddDate(0, 0, 7).Add(10*time.Hour), lasting(time.Hour)))
+
+	return []Meeting{standup(0), oneOnOne, review, standup(1), offsite, standup(2), nextWeek}
+}
+
+func TestNewReport(t *testing.T) {
+	newYork, err := time.LoadLocation("America/New_York")
+	require.NoError(t, err)
+	monday := time.Date(2018, time.October, 8, 0, 0, 0, 0, newYork)
+	from, to := WeekRange(monday)
+
+	report := NewReport(reportTestMeetings(monday), from, to, WorkingHours{})
+
+	assert.Equal(t, 5, report.Total.Meetings, "all-day events and meetings outside the range aren't counted")
+	assert.Equal(t, 120*time.Minute, report.Total.Time)
+
+	require.Len(t, report.Days, 7)
+	assert.Equal(t, "Mon Oct 8", report.Days[0].Name)
+	assert.Equal(t, 3, report.Days[0].Meetings)
+	assert.Equal(t, 90*time.Minute, report.Days[0].Time)
+	assert.Equal(t, 4*time.Hour+45*time.Minute, report.Days[0].LongestFree)
+	assert.Equal(t, monday.Add(12*time.Hour+15*time.Minute), report.Days[0].LongestFreeStart)
+	assert.Equal(t, 8*time.Hour, report.Days[3].LongestFree, "a day without meetings is free all day")
+
+	assert.Equal(t, []Load{
+		{Name: "Dana Scully", Meetings: 1, Time: time.Hour, Minutes: 60},
+		{Name: "Kevin Jithub", Meetings: 3, Time: 45 * time.Minute, Minutes: 45},
+		{Name: "You", Meetings: 1, Time: 30 * time.Minute, Minutes: 30},
+	}, report.Organizers)
+	assert.Equal(t, []Load{
+		{Name: "One-off meetings", Meetings: 2, Time: 90 * time.Minute, Minutes: 90},
+		{Name: "standup", Meetings: 3, Time: 45 * time.Minute, Minutes: 45},
+	}, report.Series)
+	assert.Equal(t, []Load{
+		{Name: "jithub.zoom.us", Meetings: 4, Time: 75 * time.Minute, Minutes: 75},
+		{Name: "zoom.us", Meetings: 1, Time: time.Hour, Minutes: 60},
+	}, report.Providers)
+	assert.Equal(t, []Load{
+		{Name: "Just you"},
+		{Name: "1:1", Meetings: 1, Time: 30 * time.Minute, Minutes: 30},
+		{Name: "3-5 people"},
+		{Name: "6-10 people", Meetings: 3, Time: 45 * time.Minute, Minutes: 45},
+		{Name: "11+ people", Meetings: 1, Time: time.Hour, Minutes: 60},
+	}, report.Attendees)
+}
+
+func TestWriteReport(t *testing.T) {
+	monday := time.Date(2018, time.October, 8, 0, 0, 0, 0, time.UTC)
+	from, to := WeekRange(monday)
+	report := NewReport(reportTestMeetings(monday), from, to, WorkingHours{})
+
+	var table bytes.Buffer
+	require.NoError(t, WriteReport(&table, "table", report))
+	assert.True(t, strings.HasPrefix(table.String(), "Mon Oct 8 to Sun Oct 14: 5 meetings, 2 hr in meetings\n"), table.String())
+	assert.Contains(t, table.String(), "Mon Oct 8   3         1 hr 30 min  4 hr 45 min from 12:15\n")
+	assert.Contains(t, table.String(), "Thu Oct 11  0         -            8 hr from 09:00\n")
+
+	var csv bytes.Buffer
+	require.NoError(t, WriteReport(&csv, "csv", report))
+	assert.True(t, strings.HasPrefix(csv.String(), "group,name,meetings,minutes,longest_free_minutes\ntotal,Total,5,120,\nday,2018-10-08,3,90,285\n"), csv.String())
+	assert.Contains(t, csv.String(), "series,standup,3,45,\n")
+
+	var output bytes.Buffer
+	require.NoError(t, WriteReport(&output, "json", report))
+	var decoded struct {
+		Total struct {
+			Minutes int `json:"minutes"`
+		} `json:"total"`
+		Days []struct {
+			LongestFreeMinutes int `json:"longest_free_minutes"`
+		} `json:"days"`
+	}
+	require.NoError(t, json.Unmarshal(output.Bytes(), &decoded))
+	assert.Equal(t, 120, decoded.Total.Minutes)
+	assert.Equal(t, 285, decoded.Days[0].LongestFreeMinutes)
+
+	assert.EqualError(t, WriteReport(&output, "pdf", report), `unknown report format "pdf", expected one of: table, csv, json`)
+}
+
+func TestParseWorkingHours(t *testing.T) {
+	hours, err := ParseWorkingHours("08:30-16:00")
+	require.NoError(t, err)
+	assert.Equal(t, WorkingHours{Start: 8*time.Hour + 30*time.Minute, End: 16 * time.Hour}, hours)
+	assert.Equal(t, "08:30-16:00", hours.String())
+
+	hours, err = ParseWorkingHours("")
+	require.NoError(t, err)
+	assert.Equal(t, DefaultWorkingHours, hours)
+
+	for _, invalid := range []string{"9-5", "17:00-09:00", "09:00"} {
+		_, err := ParseWorkingHours(invalid)
+		assert.Error(t, err, invalid)
+	}
+}
+
+func TestMeeting_SeriesAndProvider(t *testing.T) {
+	event := testEvent("standup", "1", time.Now())
+	event.RecurringEventId = "abc123"
+	meeting := NewMeeting(event)
+	assert.Equal(t, "abc123", meeting.SeriesID)
+	assert.Equal(t, "jithub.zoom.us", meeting.Provider)
+	assert.Empty(t, NewMeeting(&calendar.Event{}).Provider)
+}
@@ -0,0 +1,197 @@
+package zoom
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"math/rand"
+	"net"
+	"net/http"
+	"strconv"
+	"syscall"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// retryableReasons are the Google API error reasons for a 403 which are worth retrying.
+// Daily quotas won't recover in time, so they aren't retried.
+var retryableReasons = map[string]bool{
+	"rateLimitExceeded":     true,
+	"userRateLimitExceeded": true,
+}
+
+// RetryTransport is an http.RoundTripper which retries transient failures with jittered exponential backoff.
+// It honors Retry-After and stops retrying when the request's context deadline, e.g. from http.Client.Timeout,
+// would pass before the next attempt.
+type RetryTransport struct {
+	// Base is the transport used to make each attempt.
+	Base http.RoundTripper
+	// MaxAttempts is the maximum number of attempts per request, including the first.
+	MaxAttempts int
+	// MinBackoff is the wait before the first retry. It doubles with each retry up to MaxBackoff.
+	MinBackoff time.Duration
+	// MaxBackoff is the longest wait between attempts.
+	MaxBackoff time.Duration
+}
+
+// NewRetryTransport returns a RetryTransport with sensible defaults wrapping the base transport.
+func NewRetryTransport(base http.RoundTripper) *RetryTransport {
+	return &RetryTransport{
+		Base:        base,
+		MaxAttempts: 5,
+		MinBackoff:  250 * time.Millisecond,
+		MaxBackoff:  8 * time.Second,
+	}
+}
+
+// RoundTrip makes the request, retrying if it fails with a transient error.
+func (t *RetryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+
+	for attempt := 1; ; attempt++ {
+		if attempt > 1 && req.Body != nil && req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+			req = req.Clone(ctx)
+			req.Body = body
+		}
+
+		resp, err := t.base().RoundTrip(req)
+		if attempt >= t.MaxAttempts || ctx.Err() != nil || !isRetryable(resp, err) {
+			return resp, err
+		}
+		// Requests with a body which can't be replayed can't be retried.
+		if req.Body != nil && req.GetBody == nil {
+			return resp, err
+		}
+
+		wait := t.backoff(attempt)
+		if retryAfter, ok := parseRetryAfter(resp); ok {
+			wait = retryAfter
+		}
+		if deadline, ok := ctx.Deadline(); ok && time.Now().Add(wait).After(deadline) {
+			return resp, err
+		}
+
+		if resp != nil {
+			io.Copy(ioutil.Discard, resp.Body)
+			resp.Body.Close()
+		}
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+func (t *RetryTransport) base() http.RoundTripper {
+	if t.Base == nil {
+		return http.DefaultTransport
+	}
+	return t.Base
+}
+
+// backoff returns the jittered wait before the given retry: between half and all of MinBackoff * 2^(attempt-1),
+// capped at MaxBackoff.
+func (t *RetryTransport) backoff(attempt int) time.Duration {
+	backoff := t.MinBackoff
+	for i := 1; i < attempt && backoff < t.MaxBackoff; i++ {
+		backoff *= 2
+	}
+	if backoff > t.MaxBackoff {
+		backoff = t.MaxBackoff
+	}
+	if backoff <= 0 {
+		return 0
+	}
+	return backoff/2 + time.Duration(rand.Int63n(int64(backoff/2)+1))
+}
+
+// isRetryable returns true if the attempt failed in a way which may succeed if retried.
+func isRetryable(resp *http.Response, err error) bool {
+	if err != nil {
+		return isTransientNetworkError(err)
+	}
+
+	switch resp.StatusCode {
+	case http.StatusTooManyRequests, http.StatusInternalServerError, http.StatusBadGateway,
+		http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	case http.StatusForbidden:
+		return retryableReasons[googleErrorReason(resp)]
+	default:
+		return false
+	}
+}
+
+// isTransientNetworkError returns true if the error is a timeout or a connection which was reset or refused.
+// Other errors, like TLS and certificate failures or cancelled requests, won't go away by retrying.
+func isTransientNetworkError(err error) bool {
+	if errors.Is(err, context.Canceled) {
+		return false
+	}
+	if errors.Is(err, syscall.ECONNRESET) || errors.Is(err, syscall.ECONNREFUSED) {
+		return true
+	}
+	var netErr net.Error
+	return errors.As(err, &netErr) && netErr.Timeout()
+}
+
+// googleErrorReason returns the reason of the first error in a Google API error response.
+// The body is left intact for the caller.
+func googleErrorReason(resp *http.Response) string {
+	b, err := ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+	resp.Body = ioutil.NopCloser(bytes.NewReader(b))
+	if err != nil {
+		return ""
+	}
+
+	var body struct {
+		Error struct {
+			Errors []struct {
+				Reason string `json:"reason"`
+			} `json:"errors"`
+		} `json:"error"`
+	}
+	if json.Unmarshal(b, &body) != nil || len(body.Error.Errors) == 0 {
+		return ""
+	}
+	return body.Error.Errors[0].Reason
+}
+
+// parseRetryAfter returns the wait requested by the response's Retry-After header,
+// which is either a number of seconds or an HTTP date.
+func parseRetryAfter(resp *http.Response) (time.Duration, bool) {
+	if resp == nil {
+		return 0, false
+	}
+
+	header := resp.Header.Get("Retry-After")
+	if header == "" {
+		return 0, false
+	}
+
+	if seconds, err := strconv.Atoi(header); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+
+	if date, err := http.ParseTime(header); err == nil {
+		wait := time.Until(date)
+		if wait < 0 {
+			wait = 0
+		}
+		return wait, true
+	}
+
+	return 0, false
+}
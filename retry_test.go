@@ -0,0 +1,211 @@
+package zoom
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	calendar "google.golang.org/api/calendar/v3"
+)
+
+// newFlakyServer returns a server which responds with the failures in order, then succeeds.
+func newFlakyServer(t *testing.T, failures ...func(w http.ResponseWriter)) (*httptest.Server, *int) {
+	attempts := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		if attempts <= len(failures) {
+			failures[attempts-1](w)
+			return
+		}
+		fmt.Fprint(w, `{"items": [{"summary": "Standup", "location": "https://jithub.zoom.us/j/1"}]}`)
+	}))
+	return server, &attempts
+}
+
+func newTestRetryClient(timeout time.Duration) *http.Client {
+	return &http.Client{
+		Timeout: timeout,
+		Transport: &RetryTransport{
+			MaxAttempts: 4,
+			MinBackoff:  time.Millisecond,
+			MaxBackoff:  10 * time.Millisecond,
+		},
+	}
+}
+
+func serviceUnavailable(w http.ResponseWriter) {
+	http.Error(w, `{"error": {"code": 503, "message": "Backend Error"}}`, http.StatusServiceUnavailable)
+}
+
+func rateLimitExceeded(w http.ResponseWriter) {
+	http.Error(w, `{"error": {"code": 403, "errors": [{"reason": "rateLimitExceeded"}]}}`, http.StatusForbidden)
+}
+
+func forbidden(w http.ResponseWriter) {
+	http.Error(w, `{"error": {"code": 403, "errors": [{"reason": "forbidden"}]}}`, http.StatusForbidden)
+}
+
+func TestRetryTransport_RetriesTransientErrors(t *testing.T) {
+	server, attempts := newFlakyServer(t, serviceUnavailable, rateLimitExceeded)
+	defer server.Close()
+
+	service, err := calendar.New(newTestRetryClient(5 * time.Second))
+	require.NoError(t, err)
+	service.BasePath = server.URL
+
+	events, err := NextEvents(service, 1)
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+	assert.Equal(t, 3, *attempts)
+}
+
+func TestRetryTransport_GivesUpAfterMaxAttempts(t *testing.T) {
+	server, attempts := newFlakyServer(t, serviceUnavailable, serviceUnavailable, serviceUnavailable, serviceUnavailable, serviceUnavailable)
+	defer server.Close()
+
+	resp, err := newTestRetryClient(5 * time.Second).Get(server.URL)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusServiceUnavailable, resp.StatusCode)
+	assert.Equal(t, 4, *attempts)
+
+	// The final response's body is intact.
+	body, err := ioutil.ReadAll(resp.Body)
+	require.NoError(t, err)
+	assert.Contains(t, string(body), "Backend Error")
+}
+
+func TestRetryTransport_DoesNotRetryPermanentErrors(t *testing.T) {
+	server, attempts := newFlakyServer(t, forbidden)
+	defer server.Close()
+
+	resp, err := newTestRetryClient(5 * time.Second).Get(server.URL)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+	assert.Equal(t, 1, *attempts)
+
+	body, err := ioutil.ReadAll(resp.Body)
+	require.NoError(t, err)
+	assert.Contains(t, string(body), `"reason": "forbidden"`)
+}
+
+func TestRetryTransport_RespectsDeadline(t *testing.T) {
+	retryLater := func(w http.ResponseWriter) {
+		w.Header().Set("Retry-After", "60")
+		w.WriteHeader(http.StatusTooManyRequests)
+	}
+	server, attempts := newFlakyServer(t, retryLater)
+	defer server.Close()
+
+	start := time.Now()
+	resp, err := newTestRetryClient(time.Second).Get(server.URL)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	// Waiting 60 seconds would exceed the deadline, so it gives up immediately.
+	assert.Equal(t, http.StatusTooManyRequests, resp.StatusCode)
+	assert.Equal(t, 1, *attempts)
+	assert.True(t, time.Since(start) < time.Second)
+}
+
+func TestRetryTransport_ReplaysBody(t *testing.T) {
+	bodies := []string{}
+	attempts := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		body, _ := ioutil.ReadAll(r.Body)
+		bodies = append(bodies, string(body))
+		if attempts == 1 {
+			serviceUnavailable(w)
+		}
+	}))
+	defer server.Close()
+
+	resp, err := newTestRetryClient(5*time.Second).Post(server.URL, "text/plain", strings.NewReader("hello"))
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, []string{"hello", "hello"}, bodies)
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	testCases := []struct {
+		header   string
+		expected time.Duration
+		ok       bool
+	}{
+		{"", 0, false},
+		{"3", 3 * time.Second, true},
+		{"soon", 0, false},
+		{time.Now().Add(-time.Hour).UTC().Format(http.TimeFormat), 0, true},
+	}
+	for _, testCase := range testCases {
+		resp := &http.Response{Header: http.Header{}}
+		resp.Header.Set("Retry-After", testCase.header)
+
+		actual, ok := parseRetryAfter(resp)
+		assert.Equal(t, testCase.ok, ok, "header: %q", testCase.header)
+		assert.Equal(t, testCase.expected, actual, "header: %q", testCase.header)
+	}
+}
+
+func TestRetryTransport_Backoff(t *testing.T) {
+	transport := &RetryTransport{MinBackoff: 100 * time.Millisecond, MaxBackoff: time.Second}
+	for attempt, max := range []time.Duration{100, 200, 400, 800, 1000, 1000} {
+		max *= time.Millisecond
+		backoff := transport.backoff(attempt + 1)
+		assert.True(t, max/2 <= backoff && backoff <= max, "attempt %d: %s", attempt+1, backoff)
+	}
+}
+
+func TestRetryTransport_RetriesOnlyTransientNetworkErrors(t *testing.T) {
+	testCases := []struct {
+		err      error
+		attempts int
+	}{
+		{&net.OpError{Op: "dial", Net: "tcp", Err: os.NewSyscallError("connect", syscall.ECONNREFUSED)}, 3},
+		{&net.OpError{Op: "read", Net: "tcp", Err: os.NewSyscallError("read", syscall.ECONNRESET)}, 3},
+		{&url.Error{Op: "Get", URL: "https://www.googleapis.com", Err: timeoutError{}}, 3},
+		{x509.UnknownAuthorityError{}, 1},
+		{&tls.RecordHeaderError{Msg: "first record does not look like a TLS handshake"}, 1},
+		{context.Canceled, 1},
+	}
+	for _, testCase := range testCases {
+		attempts := 0
+		transport := &RetryTransport{
+			Base: roundTripFunc(func(r *http.Request) (*http.Response, error) {
+				attempts++
+				return nil, testCase.err
+			}),
+			MaxAttempts: 3,
+			MinBackoff:  time.Millisecond,
+			MaxBackoff:  time.Millisecond,
+		}
+
+		req, err := http.NewRequest(http.MethodGet, "https://www.googleapis.com/calendar/v3", nil)
+		require.NoError(t, err)
+		_, err = transport.RoundTrip(req)
+		assert.Error(t, err)
+		assert.Equal(t, testCase.attempts, attempts, "%v", testCase.err)
+	}
+}
+
+// timeoutError is a net.Error which timed out.
+type timeoutError struct{}
+
+func (timeoutError) Error() string   { return "i/o timeout" }
+func (timeoutError) Timeout() bool   { return true }
+func (timeoutError) Temporary() bool { return true }
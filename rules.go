@@ -0,0 +1,189 @@
+package zoom
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/pkg/errors"
+	calendar "google.golang.org/api/calendar/v3"
+)
+
+// RuleAction is what a rule does with the meetings it matches.
+type RuleAction string
+
+// The actions rules can take.
+const (
+	// RuleInclude keeps the meetings, so later rules don't apply to them.
+	RuleInclude RuleAction = "include"
+	// RuleExclude leaves the meetings out entirely.
+	RuleExclude RuleAction = "exclude"
+	// RuleNeverAutoJoin lists the meetings, but never opens them automatically.
+	RuleNeverAutoJoin RuleAction = "never-auto-join"
+)
+
+// eventColors are the names of the Calendar API's event colors, by color ID.
+var eventColors = map[string]string{
+	"lavender":  "1",
+	"sage":      "2",
+	"grape":     "3",
+	"flamingo":  "4",
+	"banana":    "5",
+	"tangerine": "6",
+	"peacock":   "7",
+	"graphite":  "8",
+	"blueberry": "9",
+	"basil":     "10",
+	"tomato":    "11",
+	"default":   "",
+}
+
+// Rule decides whether the meetings it matches are included and opened automatically. Every condition given
+// must match, so a rule without conditions matches every meeting. Rules must be parsed with ParseRules before use.
+type Rule struct {
+	// Name describes the rule. It defaults to the rule's position, e.g. "rule 2".
+	Name string
+	// Title is a case-insensitive regular expression matched against the meeting's title.
+	Title string
+	// Organizer is a case-insensitive regular expression matched against the organizer's name and email.
+	Organizer string
+	// Calendar is the ID of the calendar the meeting belongs to, e.g. a colleague's email address or a shared
+	// calendar's ID. It's the organizer's email for events created on another calendar.
+	Calendar string
+	// MinAttendees and MaxAttendees bound the number of people invited, if not 0.
+	MinAttendees int
+	MaxAttendees int
+	// Color is the event's color, by name, e.g. "tomato", or color ID. "default" matches events without one.
+	Color string
+	// Provider is the host the meeting is on, e.g. "zoom.us", which also matches its subdomains.
+	Provider string
+	// Action is what to do with the meetings the rule matches.
+	Action RuleAction
+
+	title     *regexp.Regexp
+	organizer *regexp.Regexp
+	colorID   string
+}
+
+// ParseRules validates the rules and compiles their patterns.
+func ParseRules(rules []Rule) ([]Rule, error) {
+	parsed := make([]Rule, 0, len(rules))
+	for i, rule := range rules {
+		if rule.Name == "" {
+			rule.Name = fmt.Sprintf("rule %d", i+1)
+		}
+
+		switch rule.Action {
+		case RuleInclude, RuleExclude, RuleNeverAutoJoin:
+		default:
+			return nil, errors.Errorf("unknown action %q in %s, expected include, exclude or never-auto-join", rule.Action, rule.Name)
+		}
+
+		var err error
+		if rule.Title != "" {
+			if rule.title, err = regexp.Compile("(?i)" + rule.Title); err != nil {
+				return nil, errors.Wrapf(err, "invalid title pattern in %s", rule.Name)
+			}
+		}
+		if rule.Organizer != "" {
+			if rule.organizer, err = regexp.Compile("(?i)" + rule.Organizer); err != nil {
+				return nil, errors.Wrapf(err, "invalid organizer pattern in %s", rule.Name)
+			}
+		}
+
+		if rule.MinAttendees < 0 || rule.MaxAttendees < 0 || (rule.MaxAttendees > 0 && rule.MinAttendees > rule.MaxAttendees) {
+			return nil, errors.Errorf("invalid attendee range %d-%d in %s", rule.MinAttendees, rule.MaxAttendees, rule.Name)
+		}
+
+		if rule.Color != "" {
+			colorID, ok := eventColors[strings.ToLower(rule.Color)]
+			if !ok {
+				if _, err := fmt.Sscanf(rule.Color, "%d", new(int)); err != nil {
+					return nil, errors.Errorf("unknown color %q in %s", rule.Color, rule.Name)
+				}
+				colorID = rule.Color
+			}
+			rule.colorID = colorID
+		}
+
+		parsed = append(parsed, rule)
+	}
+	return parsed, nil
+}
+
+// Matches returns true if the event meets all of the rule's conditions.
+func (r Rule) Matches(event *calendar.Event) bool {
+	if event == nil {
+		return false
+	}
+	if r.title != nil && !r.title.MatchString(event.Summary) {
+		return false
+	}
+	if r.organizer != nil && !r.organizer.MatchString(eventOrganizer(event)) {
+		return false
+	}
+	if r.Calendar != "" && (event.Organizer == nil || !strings.EqualFold(event.Organizer.Email, r.Calendar)) {
+		return false
+	}
+
+	if r.MinAttendees > 0 || r.MaxAttendees > 0 {
+		attendees := attendeeCount(event)
+		if attendees < r.MinAttendees || (r.MaxAttendees > 0 && attendees > r.MaxAttendees) {
+			return false
+		}
+	}
+
+	if r.Color != "" && event.ColorId != r.colorID {
+		return false
+	}
+
+	if r.Provider != "" {
+		webURL, ok := MeetingWebURLFromEvent(event)
+		if !ok {
+			return false
+		}
+		host, provider := strings.ToLower(webURL.Hostname()), strings.ToLower(r.Provider)
+		if host != provider && !strings.HasSuffix(host, "."+provider) {
+			return false
+		}
+	}
+	return true
+}
+
+// String describes the rule, e.g. `"webinars" (exclude)`.
+func (r Rule) String() string {
+	return fmt.Sprintf("%q (%s)", r.Name, r.Action)
+}
+
+// eventOrganizer returns the names and emails of the event's organizer and creator, one per line.
+func eventOrganizer(event *calendar.Event) string {
+	parts := []string{}
+	if event.Organizer != nil {
+		parts = append(parts, event.Organizer.DisplayName, event.Organizer.Email)
+	}
+	if event.Creator != nil {
+		parts = append(parts, event.Creator.DisplayName, event.Creator.Email)
+	}
+	return strings.Join(parts, "\n")
+}
+
+// attendeeCount returns the number of people invited to the event, excluding resources like rooms.
+func attendeeCount(event *calendar.Event) int {
+	count := 0
+	for _, attendee := range event.Attendees {
+		if !attendee.Resource {
+			count++
+		}
+	}
+	return count
+}
+
+// RuleFor returns the first of the filter's rules which matches the event.
+func (f Filter) RuleFor(event *calendar.Event) (Rule, bool) {
+	for _, rule := range f.Rules {
+		if rule.Matches(event) {
+			return rule, true
+		}
+	}
+	return Rule{}, false
+}
@@ -0,0 +1,108 @@
+package zoom
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	calendar "google.golang.org/api/calendar/v3"
+)
+
+func TestRule_Matches(t *testing.T) {
+	now := time.Now()
+	webinar := testEvent("Company Webinar: Q4 results", "1", now)
+	webinar.Organizer = &calendar.EventOrganizer{DisplayName: "Events Team", Email: "events@example.com"}
+	webinar.ColorId = "11"
+	webinar.Attendees = []*calendar.EventAttendee{
+		{Email: "a@example.com"}, {Email: "b@example.com"}, {Email: "c@example.com"},
+		{Email: "room@resource.calendar.google.com", Resource: true},
+	}
+	webinar.Location = "https://example.zoom.us/j/1"
+
+	testCases := []struct {
+		rule     Rule
+		expected bool
+	}{
+		{Rule{}, true},
+		{Rule{Title: "webinar"}, true},
+		{Rule{Title: "^standup"}, false},
+		{Rule{Organizer: "events team"}, true},
+		{Rule{Organizer: "@example\\.com$"}, true},
+		{Rule{Organizer: "kevin"}, false},
+		{Rule{Calendar: "Events@Example.com"}, true},
+		{Rule{Calendar: "kevin@example.com"}, false},
+		{Rule{MinAttendees: 3}, true},
+		{Rule{MinAttendees: 4}, false},
+		{Rule{MaxAttendees: 2}, false},
+		{Rule{MinAttendees: 1, MaxAttendees: 3}, true},
+		{Rule{Color: "tomato"}, true},
+		{Rule{Color: "11"}, true},
+		{Rule{Color: "default"}, false},
+		{Rule{Provider: "zoom.us"}, true},
+		{Rule{Provider: "example.zoom.us"}, true},
+		{Rule{Provider: "meet.google.com"}, false},
+		{Rule{Title: "webinar", Provider: "meet.google.com"}, false},
+	}
+	for _, testCase := range testCases {
+		testCase.rule.Action = RuleExclude
+		rules, err := ParseRules([]Rule{testCase.rule})
+		require.NoError(t, err)
+		assert.Equal(t, testCase.expected, rules[0].Matches(webinar), "%+v", testCase.rule)
+	}
+}
+
+func TestParseRules(t *testing.T) {
+	rules, err := ParseRules([]Rule{
+		{Name: "webinars", Title: "webinar", Action: RuleExclude},
+		{Color: "banana", Action: RuleNeverAutoJoin},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, `"webinars" (exclude)`, rules[0].String())
+	assert.Equal(t, `"rule 2" (never-auto-join)`, rules[1].String())
+
+	invalid := []Rule{
+		{Action: "ignore"},
+		{Title: "webinar(", Action: RuleExclude},
+		{Organizer: "[", Action: RuleExclude},
+		{MinAttendees: 5, MaxAttendees: 2, Action: RuleExclude},
+		{Color: "purple", Action: RuleExclude},
+	}
+	for _, rule := range invalid {
+		_, err := ParseRules([]Rule{rule})
+		assert.Error(t, err, "%+v", rule)
+	}
+}
+
+func TestFilter_Rules(t *testing.T) {
+	now := time.Now()
+	rules, err := ParseRules([]Rule{
+		{Name: "all hands", Title: "all hands webinar", Action: RuleInclude},
+		{Name: "webinars", Title: "webinar", Action: RuleExclude},
+		{Name: "big meetings", MinAttendees: 2, Action: RuleNeverAutoJoin},
+	})
+	require.NoError(t, err)
+	filter := Filter{Rules: rules}
+
+	allHands := testEvent("All Hands Webinar", "1", now)
+	webinar := testEvent("Product webinar", "2", now)
+	big := testEvent("Planning", "3", now)
+	big.Attendees = []*calendar.EventAttendee{{Email: "a@example.com"}, {Email: "b@example.com"}}
+	standup := testEvent("Standup", "4", now)
+
+	assert.True(t, filter.AutoJoins(allHands), "earlier rules take precedence")
+	assert.False(t, filter.Allows(webinar))
+	assert.False(t, filter.AutoJoins(webinar))
+	assert.True(t, filter.Allows(big))
+	assert.False(t, filter.AutoJoins(big))
+	assert.True(t, filter.AutoJoins(standup))
+
+	rule, ok := filter.RuleFor(webinar)
+	require.True(t, ok)
+	assert.Equal(t, "webinars", rule.Name)
+	_, ok = filter.RuleFor(standup)
+	assert.False(t, ok)
+
+	meetings := []Meeting{NewMeeting(big), NewMeeting(standup)}
+	assert.Equal(t, []string{"Standup"}, meetingTitles(JoinableMeetings(meetings, now, filter)))
+}
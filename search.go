@@ -0,0 +1,113 @@
+package zoom
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	calendar "google.golang.org/api/calendar/v3"
+)
+
+// EventSearcher is implemented by EventSources which can search for events on the server.
+type EventSearcher interface {
+	// SearchEvents returns the Zoom meetings between from and to which match the query, like SearchEventsBetween.
+	SearchEvents(query string, from, to time.Time) ([]*calendar.Event, error)
+}
+
+// SearchMeetings returns the Zoom meetings between from and to which match the query, best matches first.
+// If the source is an EventSearcher, the server narrows the meetings down first. Its search only matches whole
+// words, so if it finds nothing, every meeting is fetched and matched fuzzily instead.
+func SearchMeetings(source EventSource, query string, from, to time.Time) ([]Meeting, error) {
+	if searcher, ok := source.(EventSearcher); ok {
+		events, err := searcher.SearchEvents(query, from, to)
+		if err != nil && !errors.Is(err, ErrNoMeetings) {
+			return nil, err
+		}
+		if len(events) > 0 {
+			meetings := NewMeetings(events)
+			if matches := MatchMeetings(meetings, query); len(matches) > 0 {
+				return matches, nil
+			}
+			// The server matched something the fuzzy matching doesn't look at, so trust it.
+			return meetings, nil
+		}
+	}
+
+	events, err := source.EventsBetween(from, to)
+	if err != nil && !errors.Is(err, ErrNoMeetings) {
+		return nil, err
+	}
+	return MatchMeetings(NewMeetings(events), query), nil
+}
+
+// MatchMeetings returns the meetings which match the query, best matches first. Titles, organizers and attendees
+// match fuzzily, with every character of the query appearing in order, ignoring case. Descriptions and locations,
+// which are long enough to match almost anything fuzzily, must contain every word of the query.
+func MatchMeetings(meetings []Meeting, query string) []Meeting {
+	type match struct {
+		meeting Meeting
+		score   int
+	}
+	matches := []match{}
+	for _, meeting := range meetings {
+		if score := matchScore(meeting, query); score > 0 {
+			matches = append(matches, match{meeting, score})
+		}
+	}
+	sort.SliceStable(matches, func(i, j int) bool {
+		return matches[i].score > matches[j].score
+	})
+
+	matched := make([]Meeting, 0, len(matches))
+	for _, match := range matches {
+		matched = append(matched, match.meeting)
+	}
+	return matched
+}
+
+// matchScore returns how well the meeting matches the query, or 0 if it doesn't.
+// Title matches count the most, then people, then descriptions and locations.
+func matchScore(meeting Meeting, query string) int {
+	best := 0
+	consider := func(score int) {
+		if score > best {
+			best = score
+		}
+	}
+
+	if score, ok := fuzzyScore(meeting.Title, query); ok {
+		consider(3 * score)
+	}
+	people := []string{meeting.Organizer}
+	for _, attendee := range meeting.Attendees {
+		people = append(people, attendee.Name, attendee.Email)
+	}
+	for _, person := range people {
+		if person == "" {
+			continue
+		}
+		if score, ok := fuzzyScore(person, query); ok {
+			consider(2 * score)
+		}
+	}
+	if meeting.Event != nil && containsWords(meeting.Event.Description+" "+meeting.Event.Location, query) {
+		consider(len(query))
+	}
+	return best
+}
+
+// containsWords returns true if the text contains every word of the query, ignoring case.
+func containsWords(text, query string) bool {
+	words := strings.Fields(strings.ToLower(query))
+	if len(words) == 0 {
+		return false
+	}
+	text = strings.ToLower(text)
+	for _, word := range words {
+		if !strings.Contains(text, word) {
+			return false
+		}
+	}
+	return true
+}
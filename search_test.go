@@ -0,0 +1,121 @@
+package zoom
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	calendar "google.golang.org/api/calendar/v3"
+)
+
+func searchTestMeetings(now time.Time) []Meeting {
+	review := testEvent("Design Review", "1", now.Add(time.Hour))
+	review.Organizer = &calendar.EventOrganizer{DisplayName: "Kevin Jithub"}
+
+	oneOnOne := testEvent("1:1", "2", now.Add(2*time.Hour))
+	oneOnOne.Attendees = []*calendar.EventAttendee{{DisplayName: "Dana Scully", Email: "dana@jithub.com"}}
+
+	planning := testEvent("Planning", "3", now.Add(3*time.Hour))
+	planning.Description = "Quarterly roadmap planning for the design system. " + "Lots of detail here. "
+
+	return NewMeetings([]*calendar.Event{review, oneOnOne, planning})
+}
+
+func TestMatchMeetings(t *testing.T) {
+	meetings := searchTestMeetings(time.Now())
+
+	testCases := []struct {
+		query    string
+		expected []string
+	}{
+		{"design review", []string{"Design Review"}},
+		{"dsgn rev", []string{"Design Review"}},
+		{"design", []string{"Design Review", "Planning"}},
+		{"kevin", []string{"Design Review"}},
+		{"scully", []string{"1:1"}},
+		{"dana@jithub", []string{"1:1"}},
+		{"roadmap", []string{"Planning"}},
+		{"xylophone", []string{}},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.query, func(t *testing.T) {
+			assert.Equal(t, testCase.expected, meetingTitles(MatchMeetings(meetings, testCase.query)))
+		})
+	}
+}
+
+// fakeSearcher is an EventSource which can search, returning whatever events the test sets for each query.
+type fakeSearcher struct {
+	fakeSource
+	results  map[string][]*calendar.Event
+	searches []string
+}
+
+func (s *fakeSearcher) SearchEvents(query string, from, to time.Time) ([]*calendar.Event, error) {
+	s.searches = append(s.searches, query)
+	return s.results[query], nil
+}
+
+func TestSearchMeetings(t *testing.T) {
+	now := time.Now()
+	meetings := searchTestMeetings(now)
+	events := []*calendar.Event{meetings[0].Event, meetings[1].Event, meetings[2].Event}
+
+	source := &fakeSearcher{results: map[string][]*calendar.Event{
+		"design":   {meetings[0].Event, meetings[2].Event},
+		"room 101": {meetings[1].Event},
+	}}
+	source.set(events...)
+
+	t.Run("server search", func(t *testing.T) {
+		found, err := SearchMeetings(source, "design", now, now.Add(DefaultHorizon))
+		require.NoError(t, err)
+		assert.Equal(t, []string{"Design Review", "Planning"}, meetingTitles(found))
+		assert.Equal(t, 0, source.polls)
+	})
+
+	t.Run("server match the fuzzy matching misses", func(t *testing.T) {
+		found, err := SearchMeetings(source, "room 101", now, now.Add(DefaultHorizon))
+		require.NoError(t, err)
+		assert.Equal(t, []string{"1:1"}, meetingTitles(found))
+	})
+
+	t.Run("falls back to fuzzy matching", func(t *testing.T) {
+		found, err := SearchMeetings(source, "dsgn", now, now.Add(DefaultHorizon))
+		require.NoError(t, err)
+		assert.Equal(t, []string{"Design Review"}, meetingTitles(found))
+		assert.Equal(t, 1, source.polls)
+	})
+
+	t.Run("source without search", func(t *testing.T) {
+		found, err := SearchMeetings(&source.fakeSource, "scully", now, now.Add(DefaultHorizon))
+		require.NoError(t, err)
+		assert.Equal(t, []string{"1:1"}, meetingTitles(found))
+	})
+
+	assert.Equal(t, []string{"design", "room 101", "dsgn"}, source.searches)
+}
+
+func TestSearchEventsBetween(t *testing.T) {
+	mux := http.NewServeMux()
+
+	service, shutdown := newFakeGoogleCalendarService(t, mux)
+	defer shutdown()
+
+	from := time.Date(2018, time.October, 10, 0, 0, 0, 0, time.UTC)
+	mux.HandleFunc("/calendars/primary/events", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "design review", r.URL.Query().Get("q"))
+		fmt.Fprint(w, `{"items": [
+			{"summary": "Design Review", "location": "https://jithub.zoom.us/j/1"},
+			{"summary": "Design Review (in person)", "location": "Room 101"}
+		]}`)
+	})
+
+	events, err := NewGoogleSource(service).SearchEvents("design review", from, from.AddDate(0, 0, 7))
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+	assert.Equal(t, "Design Review", events[0].Summary)
+}
@@ -0,0 +1,277 @@
+package zoom
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"time"
+
+	"github.com/pkg/errors"
+	calendar "google.golang.org/api/calendar/v3"
+)
+
+// SeriesPattern matches the meetings in a recurring series, either by the series ID or by a case-insensitive
+// regular expression matched against their titles. One-off meetings never match.
+type SeriesPattern struct {
+	pattern string
+	title   *regexp.Regexp
+}
+
+// ParseSeriesPattern parses a series ID or title pattern, e.g. "standup" or "^1:1 with".
+func ParseSeriesPattern(s string) (SeriesPattern, error) {
+	title, err := regexp.Compile("(?i)" + s)
+	if err != nil {
+		return SeriesPattern{}, errors.Wrapf(err, "invalid series pattern %q", s)
+	}
+	return SeriesPattern{pattern: s, title: title}, nil
+}
+
+// Matches returns true if the event is an instance of a recurring series the pattern matches.
+func (p SeriesPattern) Matches(event *calendar.Event) bool {
+	if event == nil || event.RecurringEventId == "" || p.title == nil {
+		return false
+	}
+	return event.RecurringEventId == p.pattern || p.title.MatchString(event.Summary)
+}
+
+func (p SeriesPattern) String() string {
+	return p.pattern
+}
+
+// matchesAnySeries returns true if any of the patterns match the event.
+func matchesAnySeries(patterns []SeriesPattern, event *calendar.Event) bool {
+	for _, pattern := range patterns {
+		if pattern.Matches(event) {
+			return true
+		}
+	}
+	return false
+}
+
+// seriesHistoryKept is how many of a series' past instances SeriesHistory keeps to compare join URLs with.
+// Older instances are only counted.
+const seriesHistoryKept = 20
+
+// SeriesHistory is what's known about the instances of a recurring series, so its meetings can be numbered
+// without fetching every earlier instance each time.
+type SeriesHistory struct {
+	// Earlier is how many instances there were before the first of Instances.
+	Earlier int `json:"earlier"`
+	// Instances are the latest instances, oldest first. Cancelled instances are left out.
+	Instances []*calendar.Event `json:"instances"`
+	// FetchedTo is when the instances were fetched up to. It's zero if nothing has been fetched.
+	FetchedTo time.Time `json:"fetched_to"`
+}
+
+// SeriesInstances returns the instances of the recurring series which start before to and, if from is not zero,
+// end after from, oldest first. Cancelled instances are left out.
+func (s *GoogleSource) SeriesInstances(seriesID string, from, to time.Time) ([]*calendar.Event, error) {
+	call := s.service.Events.
+		Instances("primary", seriesID).
+		TimeMax(to.Format(googleCalendarDateTimeFormat)).
+		MaxResults(eventsPageSize).
+		Fields(eventsListFields)
+	if !from.IsZero() {
+		call = call.TimeMin(from.Format(googleCalendarDateTimeFormat))
+	}
+
+	instances := []*calendar.Event{}
+	pageToken := ""
+	for {
+		events, err := call.PageToken(pageToken).Do()
+		if err != nil {
+			return nil, classifyError(err)
+		}
+		instances = append(instances, events.Items...)
+
+		if events.NextPageToken == "" {
+			sortEventsByStart(instances)
+			return instances, nil
+		}
+		pageToken = events.NextPageToken
+	}
+}
+
+// UpdateSeriesHistory brings the series' history up to date with the instances starting before to.
+// Past instances which were already fetched aren't fetched again, so only a history without anything fetched
+// yet is fetched from the series' first instance.
+func (s *GoogleSource) UpdateSeriesHistory(seriesID string, history SeriesHistory, to, now time.Time) (SeriesHistory, error) {
+	// Upcoming instances may have changed since they were fetched.
+	from := history.FetchedTo
+	if now.Before(from) {
+		from = now
+	}
+	instances, err := s.SeriesInstances(seriesID, from, to)
+	if err != nil {
+		return history, err
+	}
+	return history.merge(instances, from, to, now), nil
+}
+
+// merge replaces the instances starting from from with those fetched until to, then counts all but
+// the latest past instances instead of keeping them. An instance in progress at from is fetched again,
+// since the Calendar API's time range matches end times, so fetched instances replace those with the same ID.
+func (h SeriesHistory) merge(instances []*calendar.Event, from, to, now time.Time) SeriesHistory {
+	fetched := map[string]bool{}
+	for _, event := range instances {
+		fetched[event.Id] = true
+	}
+
+	merged := []*calendar.Event{}
+	for _, event := range h.Instances {
+		if start, err := MeetingStartTime(event); err == nil && start.Before(from) && !fetched[event.Id] {
+			merged = append(merged, event)
+		}
+	}
+	for _, event := range instances {
+		if event.Status != eventStatusCancelled {
+			merged = append(merged, event)
+		}
+	}
+	sortEventsByStart(merged)
+
+	past := 0
+	for _, event := range merged {
+		if start, err := MeetingStartTime(event); err == nil && start.Before(now) {
+			past++
+		}
+	}
+	if extra := past - seriesHistoryKept; extra > 0 {
+		h.Earlier += extra
+		merged = merged[extra:]
+	}
+
+	h.Instances = merged
+	if to.After(h.FetchedTo) {
+		h.FetchedTo = to
+	}
+	return h
+}
+
+// SeriesCache stores the history of recurring series on disk.
+type SeriesCache struct {
+	path string
+}
+
+// NewSeriesCache returns a SeriesCache stored in the file at path.
+func NewSeriesCache(path string) *SeriesCache {
+	return &SeriesCache{path: path}
+}
+
+// NewDefaultSeriesCache returns a SeriesCache stored in the user's cache directory,
+// e.g. $XDG_CACHE_HOME/zoom/series.json.
+func NewDefaultSeriesCache() (*SeriesCache, error) {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	return NewSeriesCache(filepath.Join(dir, "zoom", "series.json")), nil
+}
+
+// Load reads the history of each series, keyed by series ID. It's empty if nothing has been cached.
+func (c *SeriesCache) Load() (map[string]SeriesHistory, error) {
+	histories := map[string]SeriesHistory{}
+	b, err := ioutil.ReadFile(c.path)
+	if os.IsNotExist(err) {
+		return histories, nil
+	}
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	if err := json.Unmarshal(b, &histories); err != nil {
+		return nil, errors.Wrapf(err, "unable to parse cache %s", c.path)
+	}
+	return histories, nil
+}
+
+// Save stores the history of each series. Series which haven't been fetched for 90 days, which have probably
+// ended, are dropped.
+func (c *SeriesCache) Save(histories map[string]SeriesHistory) error {
+	kept := map[string]SeriesHistory{}
+	for seriesID, history := range histories {
+		if time.Since(history.FetchedTo) < 90*24*time.Hour {
+			kept[seriesID] = history
+		}
+	}
+	return writeJSONAtomic(c.path, kept)
+}
+
+// AnnotateSeries numbers the meetings in recurring series and notes those whose join URL differs from the one
+// the rest of their series uses. history maps series IDs to their history, like UpdateSeriesHistory returns.
+// Meetings in a series without history aren't numbered, but their URLs are still compared with the other instances
+// among the meetings.
+func AnnotateSeries(meetings []Meeting, history map[string]SeriesHistory) []Meeting {
+	instances := map[string]map[string]*calendar.Event{}
+	add := func(event *calendar.Event) {
+		if event == nil || event.RecurringEventId == "" || event.Status == eventStatusCancelled {
+			return
+		}
+		if instances[event.RecurringEventId] == nil {
+			instances[event.RecurringEventId] = map[string]*calendar.Event{}
+		}
+		instances[event.RecurringEventId][event.Id] = event
+	}
+	for _, series := range history {
+		for _, event := range series.Instances {
+			add(event)
+		}
+	}
+	for _, meeting := range meetings {
+		add(meeting.Event)
+	}
+
+	annotated := make([]Meeting, len(meetings))
+	for i, meeting := range meetings {
+		annotated[i] = meeting
+		if meeting.SeriesID == "" {
+			continue
+		}
+
+		series := instances[meeting.SeriesID]
+		if seriesHistory, ok := history[meeting.SeriesID]; ok && !meeting.Start.IsZero() {
+			number := seriesHistory.Earlier
+			for _, event := range series {
+				if start, err := MeetingStartTime(event); err == nil && !start.After(meeting.Start) {
+					number++
+				}
+			}
+			annotated[i].Instance = number
+		}
+
+		if usual := usualSeriesURL(series, meeting.ID); meeting.URL != "" && usual != "" && usual != meeting.URL {
+			annotated[i].SeriesURL = usual
+		}
+	}
+	return annotated
+}
+
+// usualSeriesURL returns the join URL most of the series' instances other than the given one use,
+// or "" if there isn't one most of them agree on.
+func usualSeriesURL(series map[string]*calendar.Event, exceptID string) string {
+	counts := map[string]int{}
+	others := 0
+	for id, event := range series {
+		if id == exceptID {
+			continue
+		}
+		others++
+		if meetingURL, ok := MeetingURLFromEvent(event); ok {
+			counts[meetingURL.String()]++
+		}
+	}
+
+	urls := make([]string, 0, len(counts))
+	for url := range counts {
+		urls = append(urls, url)
+	}
+	sort.Strings(urls)
+	for _, url := range urls {
+		if counts[url]*2 > others {
+			return url
+		}
+	}
+	return ""
+}
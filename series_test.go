@@ -0,0 +1,204 @@
+package zoom
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	calendar "google.golang.org/api/calendar/v3"
+)
+
+func TestFilter_Series(t *testing.T) {
+	now := time.Now()
+	standup := testEvent("Daily Standup", "1", now, inSeries("standup123"))
+	oneOnOne := testEvent("1:1 with Kevin", "2", now, inSeries("weekly456"))
+	oneOff := testEvent("Standup retro", "3", now)
+
+	pattern, err := ParseSeriesPattern("standup")
+	require.NoError(t, err)
+	byID, err := ParseSeriesPattern("weekly456")
+	require.NoError(t, err)
+	assert.True(t, pattern.Matches(standup), "titles match case-insensitively")
+	assert.True(t, byID.Matches(oneOnOne))
+	assert.False(t, pattern.Matches(oneOff), "one-off meetings aren't in a series")
+
+	only := Filter{Series: []SeriesPattern{pattern}}
+	assert.True(t, only.Allows(standup))
+	assert.False(t, only.Allows(oneOnOne))
+	assert.False(t, only.Allows(oneOff))
+
+	exclude := Filter{ExcludeSeries: []SeriesPattern{byID}}
+	assert.True(t, exclude.Allows(standup))
+	assert.False(t, exclude.Allows(oneOnOne))
+	assert.True(t, exclude.Allows(oneOff))
+
+	_, err = ParseSeriesPattern("standup(")
+	assert.Error(t, err)
+}
+
+func TestAnnotateSeries(t *testing.T) {
+	monday := time.Date(2018, time.October, 8, 9, 30, 0, 0, time.UTC)
+	history := []*calendar.Event{}
+	for week := 0; week < 13; week++ {
+		history = append(history, testEvent("Standup", "1", monday.AddDate(0, 0, 7*week), inSeries("standup123")))
+	}
+	cancelled := testEvent("Standup", "1", monday.AddDate(0, 0, 7*13), inSeries("standup123"))
+	cancelled.Status = "cancelled"
+	history = append(history, cancelled)
+
+	thisWeek := testEvent("Standup", "9", monday.AddDate(0, 0, 7*14), inSeries("standup123"))
+	nextWeek := testEvent("Standup", "1", monday.AddDate(0, 0, 7*15), inSeries("standup123"))
+	oneOff := testEvent("retro", "2", monday.AddDate(0, 0, 7*14))
+	meetings := []Meeting{NewMeeting(thisWeek), NewMeeting(nextWeek), NewMeeting(oneOff)}
+
+	annotated := AnnotateSeries(meetings, map[string]SeriesHistory{"standup123": {Instances: history}})
+	assert.Equal(t, 14, annotated[0].Instance, "cancelled instances aren't counted")
+	assert.Equal(t, "zoommtg://zoom.us/join?confno=1", annotated[0].SeriesURL)
+	assert.Equal(t, "instance 14 of a recurring series", annotated[0].Recurrence())
+	assert.Equal(t, 15, annotated[1].Instance)
+	assert.Empty(t, annotated[1].SeriesURL)
+	assert.Zero(t, annotated[2].Instance)
+	assert.Empty(t, annotated[2].Recurrence())
+
+	// Without history, meetings aren't numbered but are still compared with each other.
+	meetings = append(meetings, NewMeeting(testEvent("Standup", "1", monday.AddDate(0, 0, 7*16), inSeries("standup123"))))
+	annotated = AnnotateSeries(meetings, nil)
+	assert.Zero(t, annotated[0].Instance)
+	assert.Equal(t, "part of a recurring series", annotated[0].Recurrence())
+	assert.Equal(t, "zoommtg://zoom.us/join?confno=1", annotated[0].SeriesURL)
+
+	// Earlier instances which are only counted still count.
+	annotated = AnnotateSeries(meetings, map[string]SeriesHistory{"standup123": {Earlier: 100, Instances: history}})
+	assert.Equal(t, 114, annotated[0].Instance)
+}
+
+func TestGoogleSource_SeriesInstances(t *testing.T) {
+	mux := http.NewServeMux()
+	service, shutdown := newFakeGoogleCalendarService(t, mux)
+	defer shutdown()
+
+	monday := time.Date(2018, time.October, 8, 9, 30, 0, 0, time.UTC)
+	timeMin, timeMax := "", ""
+	mux.HandleFunc("/calendars/primary/events/standup123/instances", func(w http.ResponseWriter, r *http.Request) {
+		timeMin, timeMax = r.URL.Query().Get("timeMin"), r.URL.Query().Get("timeMax")
+		if r.URL.Query().Get("pageToken") == "" {
+			fmt.Fprintf(w, `{"items": %s, "nextPageToken": "next"}`, syncTestEvents(t,
+				testEvent("Standup", "1", monday.AddDate(0, 0, 7), inSeries("standup123")),
+			))
+			return
+		}
+		fmt.Fprintf(w, `{"items": %s}`, syncTestEvents(t,
+			testEvent("Standup", "1", monday, inSeries("standup123")),
+		))
+	})
+
+	instances, err := NewGoogleSource(service).SeriesInstances("standup123", time.Time{}, monday.AddDate(0, 0, 8))
+	require.NoError(t, err)
+	require.Len(t, instances, 2)
+	assert.Equal(t, "standup123_20181008T093000Z", instances[0].Id)
+	assert.Empty(t, timeMin)
+	assert.Equal(t, monday.AddDate(0, 0, 8).Format(time.RFC3339), timeMax)
+
+	_, err = NewGoogleSource(service).SeriesInstances("standup123", monday, monday.AddDate(0, 0, 8))
+	require.NoError(t, err)
+	assert.Equal(t, monday.Format(time.RFC3339), timeMin)
+}
+
+func TestGoogleSource_UpdateSeriesHistory(t *testing.T) {
+	mux := http.NewServeMux()
+	service, shutdown := newFakeGoogleCalendarService(t, mux)
+	defer shutdown()
+
+	monday := time.Date(2018, time.October, 8, 9, 30, 0, 0, time.UTC)
+	now := monday.AddDate(0, 0, 7*30)
+	weekly := func(from, to int) []*calendar.Event {
+		events := []*calendar.Event{}
+		for week := from; week < to; week++ {
+			events = append(events, testEvent("Standup", "1", monday.AddDate(0, 0, 7*week), inSeries("standup123")))
+		}
+		return events
+	}
+
+	requests := []string{}
+	mux.HandleFunc("/calendars/primary/events/standup123/instances", func(w http.ResponseWriter, r *http.Request) {
+		timeMin := r.URL.Query().Get("timeMin")
+		requests = append(requests, timeMin)
+		switch timeMin {
+		case "":
+			fmt.Fprintf(w, `{"items": %s}`, syncTestEvents(t, weekly(0, 31)...))
+		case now.Format(time.RFC3339):
+			fmt.Fprintf(w, `{"items": %s}`, syncTestEvents(t, weekly(30, 32)...))
+		default:
+			t.Errorf("unexpected timeMin %s", timeMin)
+			http.Error(w, "unexpected timeMin", http.StatusBadRequest)
+		}
+	})
+	source := NewGoogleSource(service)
+
+	// The first time, the whole history is fetched, and all but the latest instances are only counted.
+	history, err := source.UpdateSeriesHistory("standup123", SeriesHistory{}, now.AddDate(0, 0, 1), now)
+	require.NoError(t, err)
+	assert.Equal(t, 10, history.Earlier)
+	assert.Len(t, history.Instances, 21, "the 20 past instances and the upcoming one")
+	assert.Equal(t, now.AddDate(0, 0, 1), history.FetchedTo)
+
+	// Later, only the instances from now on are fetched again.
+	history, err = source.UpdateSeriesHistory("standup123", history, now.AddDate(0, 0, 8), now)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"", now.Format(time.RFC3339)}, requests)
+	assert.Equal(t, 10, history.Earlier)
+	assert.Len(t, history.Instances, 22)
+
+	next := NewMeeting(testEvent("Standup", "1", now.AddDate(0, 0, 7), inSeries("standup123")))
+	annotated := AnnotateSeries([]Meeting{next}, map[string]SeriesHistory{"standup123": history})
+	assert.Equal(t, 32, annotated[0].Instance)
+
+	cache := NewSeriesCache(filepath.Join(t.TempDir(), "zoom", "series.json"))
+	histories, err := cache.Load()
+	require.NoError(t, err)
+	assert.Empty(t, histories)
+	history.FetchedTo = time.Now()
+	require.NoError(t, cache.Save(map[string]SeriesHistory{"standup123": history, "ended": {FetchedTo: time.Now().AddDate(0, -6, 0)}}))
+	histories, err = cache.Load()
+	require.NoError(t, err)
+	require.Contains(t, histories, "standup123")
+	assert.NotContains(t, histories, "ended", "series not fetched for a long time are dropped")
+	assert.Equal(t, 10, histories["standup123"].Earlier)
+}
+
+func TestSeriesHistory_MergeInstanceInProgress(t *testing.T) {
+	monday := time.Date(2018, time.October, 8, 9, 30, 0, 0, time.UTC)
+	weekly := func(week int, title string) *calendar.Event {
+		return testEvent(title, "1", monday.AddDate(0, 0, 7*week), lasting(time.Hour), inSeries("standup123"))
+	}
+	history := SeriesHistory{
+		Earlier:   3,
+		Instances: []*calendar.Event{weekly(0, "Standup"), weekly(1, "Standup"), weekly(2, "Standup")},
+		FetchedTo: monday.AddDate(0, 0, 14).Add(30 * time.Minute),
+	}
+
+	// The third standup was still in progress when the history was last fetched up to, so it's fetched again.
+	from := history.FetchedTo
+	merged := history.merge([]*calendar.Event{weekly(2, "Renamed standup"), weekly(3, "Renamed standup")}, from, monday.AddDate(0, 0, 22), monday.AddDate(0, 0, 21))
+
+	assert.Equal(t, 3, merged.Earlier)
+	require.Len(t, merged.Instances, 4)
+	assert.Equal(t, []string{"Standup", "Standup", "Renamed standup", "Renamed standup"}, summaries(merged.Instances))
+}
+
+func TestRenderMeeting_Recurrence(t *testing.T) {
+	tmpl, err := ParseTemplate(DefaultTemplate)
+	require.NoError(t, err)
+
+	meeting := NewMeeting(testEvent("Standup", "1", time.Now().Add(time.Hour), inSeries("standup123")))
+	meeting.Instance = 14
+
+	var output bytes.Buffer
+	require.NoError(t, RenderMeeting(&output, tmpl, meeting))
+	assert.Contains(t, output.String(), "This is instance 14 of a recurring series.\n")
+}
@@ -0,0 +1,251 @@
+package zoom
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"log"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// Server is a small local REST API for other tools, like editor plugins or an "on air" light,
+// to query the upcoming meetings and join them. It serves:
+//
+//	GET  /meetings/next       the next meeting, or 404 if there is none
+//	GET  /meetings/today      today's meetings
+//	POST /meetings/next/join  opens the next meeting's join URL
+//
+// Responses are JSON. Errors are returned as {"error": "..."}.
+//
+// Browsers can reach a server on localhost too, so requests from another origin are refused, and without a Token
+// requests must be addressed to a loopback host, so a web page can't use DNS rebinding to read meetings or join them.
+type Server struct {
+	// Source provides the meetings.
+	Source EventSource
+	// Opener opens join URLs. If it's nil, joining isn't supported.
+	Opener Opener
+	// Hooks run before a meeting is joined. A before-join hook which aborts stops the meeting being opened.
+	Hooks []MeetingHook
+	// Logger logs hook failures. If it's nil, they aren't logged.
+	Logger *log.Logger
+	// Token is the bearer token clients must send in the Authorization header. If it's empty, no token is required.
+	Token string
+	// Horizon is how far ahead to look for the next meeting. It defaults to DefaultHorizon.
+	Horizon time.Duration
+	// Clock defaults to SystemClock.
+	Clock Clock
+}
+
+// ServerMeeting is a meeting as returned by the Server, along with when it starts relative to now.
+type ServerMeeting struct {
+	Meeting
+	Until   string `json:"until"`
+	Started bool   `json:"started"`
+	Soon    bool   `json:"soon"`
+	// InProgress is true if the meeting has started but not ended yet.
+	InProgress bool `json:"in_progress"`
+	// Duration is how long the meeting lasts in minutes, or 0 if it doesn't have an end time.
+	Duration int `json:"duration_minutes,omitempty"`
+}
+
+// ServeHTTP routes the request to the matching endpoint.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if !s.local(r) {
+		writeJSONError(w, http.StatusForbidden, "cross-origin and non-local requests aren't allowed")
+		return
+	}
+	if !s.authorized(r) {
+		w.Header().Set("WWW-Authenticate", `Bearer realm="zoom"`)
+		writeJSONError(w, http.StatusUnauthorized, "missing or invalid bearer token")
+		return
+	}
+
+	switch r.URL.Path {
+	case "/meetings/next":
+		if allowMethod(w, r, http.MethodGet) {
+			s.serveNext(w)
+		}
+	case "/meetings/today":
+		if allowMethod(w, r, http.MethodGet) {
+			s.serveToday(w)
+		}
+	case "/meetings/next/join":
+		if allowMethod(w, r, http.MethodPost) {
+			s.serveJoin(w, r)
+		}
+	default:
+		writeJSONError(w, http.StatusNotFound, "not found")
+	}
+}
+
+func (s *Server) serveNext(w http.ResponseWriter) {
+	meeting, ok, err := s.next()
+	if err != nil {
+		writeSourceError(w, err)
+		return
+	}
+	if !ok {
+		writeJSONError(w, http.StatusNotFound, "no upcoming meetings")
+		return
+	}
+	writeJSON(w, http.StatusOK, s.serverMeeting(meeting))
+}
+
+func (s *Server) serveToday(w http.ResponseWriter) {
+	from, to := DayRange(s.clock().Now())
+	events, err := s.Source.EventsBetween(from, to)
+	if err != nil && !errors.Is(err, ErrNoMeetings) {
+		writeSourceError(w, err)
+		return
+	}
+
+	meetings := make([]ServerMeeting, 0, len(events))
+	for _, event := range events {
+		meetings = append(meetings, s.serverMeeting(NewMeeting(event)))
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{"meetings": meetings})
+}
+
+func (s *Server) serveJoin(w http.ResponseWriter, r *http.Request) {
+	if s.Opener == nil {
+		writeJSONError(w, http.StatusNotImplemented, "joining meetings is not enabled")
+		return
+	}
+
+	meeting, ok, err := s.next()
+	if err != nil {
+		writeSourceError(w, err)
+		return
+	}
+	if !ok {
+		writeJSONError(w, http.StatusNotFound, "no upcoming meetings")
+		return
+	}
+	if meeting.URL == "" {
+		writeJSONError(w, http.StatusUnprocessableEntity, "no Zoom URL found in the meeting")
+		return
+	}
+
+	var aborted *HookAbortedError
+	err = JoinMeeting(r.Context(), s.Opener, s.Hooks, meeting, s.clock().Now(), s.Logger)
+	if errors.As(err, &aborted) {
+		writeJSONError(w, http.StatusConflict, "a before-join hook stopped the meeting being opened: "+err.Error())
+		return
+	}
+	if err != nil {
+		writeJSONError(w, http.StatusInternalServerError, "unable to open meeting: "+err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, s.serverMeeting(meeting))
+}
+
+// next returns the next meeting, if there is one.
+func (s *Server) next() (Meeting, bool, error) {
+	horizon := s.Horizon
+	if horizon <= 0 {
+		horizon = DefaultHorizon
+	}
+
+	events, err := s.Source.NextEventsWithin(1, horizon)
+	if err != nil && !errors.Is(err, ErrNoMeetings) {
+		return Meeting{}, false, err
+	}
+	if len(events) == 0 {
+		return Meeting{}, false, nil
+	}
+	return NewMeeting(events[0]), true, nil
+}
+
+func (s *Server) serverMeeting(meeting Meeting) ServerMeeting {
+	now := s.clock().Now()
+	return ServerMeeting{
+		Meeting: meeting,
+		Until:   meeting.Until(),
+		Started: !meeting.Start.IsZero() && !now.Before(meeting.Start),
+		Soon:    isMeetingSoonAt(meeting.Event, now),
+
+		InProgress: meeting.inProgressAt(now),
+		Duration:   int(meeting.Duration().Minutes()),
+	}
+}
+
+// authorized returns true if no token is required or the request has the right bearer token.
+func (s *Server) authorized(r *http.Request) bool {
+	if s.Token == "" {
+		return true
+	}
+	header := r.Header.Get("Authorization")
+	if !strings.HasPrefix(header, "Bearer ") {
+		return false
+	}
+	token := strings.TrimPrefix(header, "Bearer ")
+	return subtle.ConstantTimeCompare([]byte(token), []byte(s.Token)) == 1
+}
+
+// local returns false if the request comes from a web page on another origin or, when no token is required, if
+// it's addressed to a host other than localhost or a loopback address.
+func (s *Server) local(r *http.Request) bool {
+	if origin := r.Header.Get("Origin"); origin != "" {
+		u, err := url.Parse(origin)
+		if err != nil || u.Host != r.Host {
+			return false
+		}
+	}
+	if r.Header.Get("Sec-Fetch-Site") == "cross-site" {
+		return false
+	}
+	if s.Token != "" {
+		return true
+	}
+	host, _, err := net.SplitHostPort(r.Host)
+	if err != nil {
+		host = r.Host
+	}
+	host = strings.Trim(host, "[]")
+	if ip := net.ParseIP(host); ip != nil {
+		return ip.IsLoopback()
+	}
+	return strings.EqualFold(host, "localhost")
+}
+
+func (s *Server) clock() Clock {
+	if s.Clock == nil {
+		return SystemClock
+	}
+	return s.Clock
+}
+
+// allowMethod returns true if the request uses the method, otherwise responding with 405 Method Not Allowed.
+func allowMethod(w http.ResponseWriter, r *http.Request, method string) bool {
+	if r.Method == method {
+		return true
+	}
+	w.Header().Set("Allow", method)
+	writeJSONError(w, http.StatusMethodNotAllowed, "method not allowed")
+	return false
+}
+
+// writeSourceError responds to a failure fetching meetings. The calendar is upstream of the server,
+// so failures are reported as 502 Bad Gateway, or 504 Gateway Timeout if it couldn't be reached.
+func writeSourceError(w http.ResponseWriter, err error) {
+	status := http.StatusBadGateway
+	if errors.Is(err, ErrNetwork) {
+		status = http.StatusGatewayTimeout
+	}
+	writeJSONError(w, status, err.Error())
+}
+
+func writeJSONError(w http.ResponseWriter, status int, message string) {
+	writeJSON(w, status, map[string]string{"error": message})
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
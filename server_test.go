@@ -0,0 +1,201 @@
+package zoom
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestServer(now time.Time) (*Server, *fakeSource, *fakeOpener) {
+	source := &fakeSource{}
+	opener := &fakeOpener{}
+	server := &Server{
+		Source: source,
+		Opener: opener,
+		Clock:  &fakeClock{now: now},
+	}
+	return server, source, opener
+}
+
+func serveTestRequest(t *testing.T, server *Server, method, path, token string) (*httptest.ResponseRecorder, map[string]interface{}) {
+	t.Helper()
+
+	req := httptest.NewRequest(method, path, nil)
+	req.Host = "localhost:8765"
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	w := httptest.NewRecorder()
+	server.ServeHTTP(w, req)
+
+	assert.Equal(t, "application/json; charset=utf-8", w.Header().Get("Content-Type"))
+	body := map[string]interface{}{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	return w, body
+}
+
+func TestServer_Next(t *testing.T) {
+	now := time.Now()
+	server, source, _ := newTestServer(now)
+	source.set(testEvent("standup", "123", now.Add(3*time.Minute)), testEvent("retro", "456", now.Add(time.Hour)))
+
+	w, body := serveTestRequest(t, server, http.MethodGet, "/meetings/next", "")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "standup", body["title"])
+	assert.Equal(t, "zoommtg://zoom.us/join?confno=123", body["url"])
+	assert.Equal(t, false, body["started"])
+	assert.Equal(t, true, body["soon"])
+}
+
+func TestServer_NextNone(t *testing.T) {
+	server, source, _ := newTestServer(time.Now())
+	source.err = &NoMeetingsError{Horizon: DefaultHorizon}
+
+	w, body := serveTestRequest(t, server, http.MethodGet, "/meetings/next", "")
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.Equal(t, "no upcoming meetings", body["error"])
+}
+
+func TestServer_Today(t *testing.T) {
+	now := time.Now()
+	server, source, _ := newTestServer(now)
+	source.set(testEvent("standup", "123", now.Add(-time.Hour)), testEvent("retro", "456", now.Add(time.Minute)))
+
+	w, body := serveTestRequest(t, server, http.MethodGet, "/meetings/today", "")
+	assert.Equal(t, http.StatusOK, w.Code)
+	require.Len(t, body["meetings"], 2)
+	first := body["meetings"].([]interface{})[0].(map[string]interface{})
+	assert.Equal(t, "standup", first["title"])
+	assert.Equal(t, true, first["started"])
+}
+
+func TestServer_SoonInProgress(t *testing.T) {
+	now := time.Date(2018, time.October, 10, 9, 0, 0, 0, time.UTC)
+	server, source, _ := newTestServer(now)
+	source.set(
+		testEvent("workshop", "123", now.Add(-20*time.Minute), lasting(time.Hour)),
+		testEvent("standup", "456", now.Add(-time.Hour), lasting(30*time.Minute)),
+		testEvent("retro", "789", now.Add(time.Hour), lasting(time.Hour)),
+	)
+
+	w, body := serveTestRequest(t, server, http.MethodGet, "/meetings/today", "")
+	assert.Equal(t, http.StatusOK, w.Code)
+	soon := map[string]interface{}{}
+	for _, meeting := range body["meetings"].([]interface{}) {
+		meeting := meeting.(map[string]interface{})
+		soon[meeting["title"].(string)] = meeting["soon"]
+	}
+	// The workshop is in progress, so zoom would open it now, like a meeting about to start.
+	assert.Equal(t, map[string]interface{}{"workshop": true, "standup": false, "retro": false}, soon)
+}
+
+func TestServer_Join(t *testing.T) {
+	now := time.Now()
+	server, source, opener := newTestServer(now)
+	source.set(testEvent("standup", "123", now.Add(time.Hour)))
+
+	w, body := serveTestRequest(t, server, http.MethodPost, "/meetings/next/join", "")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "standup", body["title"])
+	assert.Equal(t, []string{"zoommtg://zoom.us/join?confno=123"}, opener.opened)
+
+	server.Opener = nil
+	w, _ = serveTestRequest(t, server, http.MethodPost, "/meetings/next/join", "")
+	assert.Equal(t, http.StatusNotImplemented, w.Code)
+}
+
+func TestServer_Errors(t *testing.T) {
+	server, source, opener := newTestServer(time.Now())
+
+	testCases := []struct {
+		name   string
+		method string
+		path   string
+		err    error
+		status int
+	}{
+		{"unknown path", http.MethodGet, "/meetings", nil, http.StatusNotFound},
+		{"wrong method", http.MethodGet, "/meetings/next/join", nil, http.StatusMethodNotAllowed},
+		{"unauthorized upstream", http.MethodGet, "/meetings/next", &APIError{Kind: ErrUnauthorized}, http.StatusBadGateway},
+		{"network", http.MethodGet, "/meetings/today", &APIError{Kind: ErrNetwork}, http.StatusGatewayTimeout},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			source.err = testCase.err
+			w, body := serveTestRequest(t, server, testCase.method, testCase.path, "")
+			assert.Equal(t, testCase.status, w.Code)
+			assert.NotEmpty(t, body["error"])
+		})
+	}
+	assert.Empty(t, opener.opened)
+}
+
+func TestServer_Token(t *testing.T) {
+	now := time.Now()
+	server, source, opener := newTestServer(now)
+	server.Token = "s3cret"
+	source.set(testEvent("standup", "123", now.Add(time.Minute)))
+
+	for _, token := range []string{"", "wrong"} {
+		w, body := serveTestRequest(t, server, http.MethodPost, "/meetings/next/join", token)
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+		assert.Equal(t, `Bearer realm="zoom"`, w.Header().Get("WWW-Authenticate"))
+		assert.NotEmpty(t, body["error"])
+	}
+	assert.Empty(t, opener.opened)
+
+	w, _ := serveTestRequest(t, server, http.MethodPost, "/meetings/next/join", "s3cret")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Len(t, opener.opened, 1)
+}
+
+func TestServer_Local(t *testing.T) {
+	now := time.Now()
+	server, source, opener := newTestServer(now)
+	source.set(testEvent("standup", "123", now.Add(time.Minute)))
+
+	testCases := []struct {
+		host, origin string
+		status       int
+	}{
+		{"localhost:8765", "", http.StatusOK},
+		{"127.0.0.1:8765", "http://127.0.0.1:8765", http.StatusOK},
+		{"[::1]:8765", "", http.StatusOK},
+		{"localhost", "", http.StatusOK},
+		{"localhost:8765", "https://evil.example.com", http.StatusForbidden},
+		{"localhost:8765", "http://localhost:3000", http.StatusForbidden},
+		{"localhost:8765", "null", http.StatusForbidden},
+		{"evil.example.com:8765", "", http.StatusForbidden},
+		{"evil.example.com:8765", "http://evil.example.com:8765", http.StatusForbidden},
+	}
+	for _, testCase := range testCases {
+		req := httptest.NewRequest(http.MethodPost, "/meetings/next/join", nil)
+		req.Host = testCase.host
+		if testCase.origin != "" {
+			req.Header.Set("Origin", testCase.origin)
+		}
+		w := httptest.NewRecorder()
+		server.ServeHTTP(w, req)
+		assert.Equal(t, testCase.status, w.Code, "%+v", testCase)
+	}
+	assert.Len(t, opener.opened, 4)
+
+	// With a token, the server can be reached by other names, but still not from web pages.
+	server.Token = "s3cret"
+	req := httptest.NewRequest(http.MethodGet, "/meetings/next", nil)
+	req.Host = "laptop.local:8765"
+	req.Header.Set("Authorization", "Bearer s3cret")
+	w := httptest.NewRecorder()
+	server.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	req.Header.Set("Origin", "https://evil.example.com")
+	w = httptest.NewRecorder()
+	server.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusForbidden, w.Code)
+}
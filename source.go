@@ -0,0 +1,95 @@
+package zoom
+
+import (
+	"sort"
+	"time"
+
+	calendar "google.golang.org/api/calendar/v3"
+)
+
+// EventSource provides Zoom meetings from a calendar.
+type EventSource interface {
+	// NextEventsWithin returns the next N Zoom meetings which start within the horizon, like NextEventsWithin.
+	NextEventsWithin(count int, horizon time.Duration) ([]*calendar.Event, error)
+
+	// EventsBetween returns the Zoom meetings between from and to, like EventsBetween.
+	EventsBetween(from, to time.Time) ([]*calendar.Event, error)
+}
+
+// GoogleSource is an EventSource which lists events from the Google Calendar API on every call.
+type GoogleSource struct {
+	// Filter decides which meetings are returned.
+	Filter Filter
+
+	service *calendar.Service
+}
+
+// NewGoogleSource returns an EventSource for the primary calendar of the service.
+func NewGoogleSource(service *calendar.Service) *GoogleSource {
+	return &GoogleSource{service: service}
+}
+
+// NextEventsWithin returns the next N Zoom meetings which start within the horizon.
+func (s *GoogleSource) NextEventsWithin(count int, horizon time.Duration) ([]*calendar.Event, error) {
+	return nextEventsWithin(s.service, count, horizon, s.Filter)
+}
+
+// EventsBetween returns the Zoom meetings between from and to.
+func (s *GoogleSource) EventsBetween(from, to time.Time) ([]*calendar.Event, error) {
+	return searchEventsBetween(s.service, "", from, to, s.Filter)
+}
+
+// SearchEvents returns the Zoom meetings between from and to which match the query, searching on the server.
+func (s *GoogleSource) SearchEvents(query string, from, to time.Time) ([]*calendar.Event, error) {
+	return searchEventsBetween(s.service, query, from, to, s.Filter)
+}
+
+// selectEvents returns up to count Zoom meetings allowed by the filter, or all of them if count is 0,
+// starting no earlier than from and, if to is not zero, before to, ordered by start time.
+func selectEvents(events []*calendar.Event, from, to time.Time, count int, filter Filter) []*calendar.Event {
+	selected := []*calendar.Event{}
+	for _, event := range events {
+		if !eventOverlaps(event, from, to) {
+			continue
+		}
+		if !filter.Allows(event) {
+			continue
+		}
+		if _, ok := MeetingURLFromEvent(event); !ok {
+			continue
+		}
+		selected = append(selected, event)
+	}
+
+	sortEventsByStart(selected)
+	if count > 0 && len(selected) > count {
+		selected = selected[:count]
+	}
+	return selected
+}
+
+// eventOverlaps returns true if the event starts before to, unless to is zero, and hasn't ended by from,
+// like the Calendar API's time range. Events without an end time must start at or after from.
+func eventOverlaps(event *calendar.Event, from, to time.Time) bool {
+	startTime, err := MeetingStartTime(event)
+	if err != nil || (!to.IsZero() && !startTime.Before(to)) {
+		return false
+	}
+	if endTime, err := MeetingEndTime(event); err == nil && endTime.After(startTime) {
+		return endTime.After(from)
+	}
+	return !startTime.Before(from)
+}
+
+// sortEventsByStart sorts the events by their start time, keeping events without one in place relative to each other.
+// Events starting at the same time are ranked by the user's response, accepted before tentative.
+func sortEventsByStart(events []*calendar.Event) {
+	sort.SliceStable(events, func(i, j int) bool {
+		a, _ := MeetingStartTime(events[i])
+		b, _ := MeetingStartTime(events[j])
+		if a.Equal(b) {
+			return responseRank(events[i]) < responseRank(events[j])
+		}
+		return a.Before(b)
+	})
+}
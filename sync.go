@@ -0,0 +1,246 @@
+package zoom
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	calendar "google.golang.org/api/calendar/v3"
+	"google.golang.org/api/googleapi"
+)
+
+const (
+	// syncLookback is how far into the past a full sync reaches.
+	syncLookback = 24 * time.Hour
+	// syncWindow is how far into the future a full sync reaches.
+	syncWindow = 30 * 24 * time.Hour
+	// syncMinRemaining is how much of the synced window must remain ahead before a full sync extends it.
+	syncMinRemaining = 7 * 24 * time.Hour
+)
+
+// SyncedSource is an EventSource which keeps a local copy of the primary calendar up to date
+// using the Calendar API's incremental sync, so each query only fetches what changed since the last one.
+// The copy is persisted to disk so later runs can sync incrementally too.
+type SyncedSource struct {
+	// Filter decides which meetings are returned. Only meetings cancelled since the last full sync are kept,
+	// so IncludeCancelled doesn't find meetings which were cancelled earlier.
+	Filter Filter
+
+	service *calendar.Service
+	path    string
+
+	mu    sync.Mutex
+	store *syncStore
+}
+
+// syncStore is the local copy of the calendar.
+type syncStore struct {
+	SyncToken string    `json:"sync_token"`
+	SyncedAt  time.Time `json:"synced_at"`
+	// WindowStart and WindowEnd are the range of times the full sync covered.
+	// Changes to any event are synced, but events outside the window which never changed are missing.
+	WindowStart time.Time                  `json:"window_start"`
+	WindowEnd   time.Time                  `json:"window_end"`
+	Events      map[string]*calendar.Event `json:"events"`
+}
+
+// NewSyncedSource returns a SyncedSource for the service's primary calendar, persisted to the file at path.
+func NewSyncedSource(service *calendar.Service, path string) *SyncedSource {
+	return &SyncedSource{service: service, path: path}
+}
+
+// NewDefaultSyncedSource returns a SyncedSource persisted in the user's cache directory,
+// e.g. $XDG_CACHE_HOME/zoom/sync.json.
+func NewDefaultSyncedSource(service *calendar.Service) (*SyncedSource, error) {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	return NewSyncedSource(service, filepath.Join(dir, "zoom", "sync.json")), nil
+}
+
+// Sync brings the local copy up to date, performing a full sync if there is no sync token,
+// the token has expired or the synced window doesn't reach far enough ahead.
+func (s *SyncedSource) Sync() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.syncLocked(time.Now())
+}
+
+func (s *SyncedSource) syncLocked(until time.Time) error {
+	if s.store == nil {
+		s.store = s.load()
+	}
+
+	now := time.Now()
+	if s.store.SyncToken == "" || s.store.WindowEnd.Before(until) || s.store.WindowEnd.Sub(now) < syncMinRemaining {
+		return s.fullSync(now, until)
+	}
+
+	err := s.incrementalSync()
+	var apiErr *googleapi.Error
+	if errors.As(err, &apiErr) && apiErr.Code == http.StatusGone {
+		// The sync token expired, so start over.
+		return s.fullSync(now, until)
+	}
+	return err
+}
+
+// fullSync replaces the local copy with every event in the window around now.
+func (s *SyncedSource) fullSync(now, until time.Time) error {
+	windowEnd := now.Add(syncWindow)
+	if windowEnd.Before(until) {
+		windowEnd = until
+	}
+
+	store := &syncStore{
+		WindowStart: now.Add(-syncLookback),
+		WindowEnd:   windowEnd,
+		Events:      map[string]*calendar.Event{},
+	}
+
+	call := s.service.Events.
+		List("primary").
+		SingleEvents(true).
+		TimeMin(store.WindowStart.Format(googleCalendarDateTimeFormat)).
+		TimeMax(store.WindowEnd.Format(googleCalendarDateTimeFormat)).
+		MaxResults(eventsPageSize).
+		Fields(eventsListFields)
+
+	syncToken, err := eachSyncedEvent(call, func(event *calendar.Event) {
+		if event.Status != eventStatusCancelled {
+			store.Events[event.Id] = event
+		}
+	})
+	if err != nil {
+		return err
+	}
+
+	store.SyncToken = syncToken
+	store.SyncedAt = now
+	s.store = store
+	return s.save()
+}
+
+// incrementalSync applies the events inserted, updated and cancelled since the last sync.
+func (s *SyncedSource) incrementalSync() error {
+	call := s.service.Events.
+		List("primary").
+		SingleEvents(true).
+		SyncToken(s.store.SyncToken).
+		MaxResults(eventsPageSize).
+		Fields(eventsListFields)
+
+	changed := map[string]*calendar.Event{}
+	syncToken, err := eachSyncedEvent(call, func(event *calendar.Event) {
+		changed[event.Id] = event
+	})
+	if err != nil {
+		return err
+	}
+
+	// Only apply the changes once every page has been fetched, so a failure leaves the copy consistent.
+	for id, event := range changed {
+		if event.Status != eventStatusCancelled {
+			s.store.Events[id] = event
+			continue
+		}
+		// Cancellations only include the event's ID, so mark the local copy as cancelled instead of replacing it.
+		// Events already returned to callers may still be in use, so the copy is replaced rather than modified.
+		if existing, ok := s.store.Events[id]; ok {
+			cancelled := *existing
+			cancelled.Status = eventStatusCancelled
+			s.store.Events[id] = &cancelled
+		}
+	}
+	s.store.SyncToken = syncToken
+	s.store.SyncedAt = time.Now()
+	return s.save()
+}
+
+// eachSyncedEvent calls fn for each event returned by the call, fetching subsequent pages as needed,
+// and returns the sync token from the last page.
+func eachSyncedEvent(call *calendar.EventsListCall, fn func(*calendar.Event)) (string, error) {
+	pageToken := ""
+	for {
+		events, err := call.PageToken(pageToken).Do()
+		if err != nil {
+			return "", classifyError(err)
+		}
+
+		for _, event := range events.Items {
+			fn(event)
+		}
+
+		if events.NextPageToken == "" {
+			return events.NextSyncToken, nil
+		}
+		pageToken = events.NextPageToken
+	}
+}
+
+// load reads the local copy from disk, returning an empty copy if it's missing or unreadable.
+func (s *SyncedSource) load() *syncStore {
+	store := &syncStore{Events: map[string]*calendar.Event{}}
+
+	b, err := ioutil.ReadFile(s.path)
+	if err != nil {
+		return store
+	}
+	if err := json.Unmarshal(b, store); err != nil || store.Events == nil {
+		return &syncStore{Events: map[string]*calendar.Event{}}
+	}
+	return store
+}
+
+func (s *SyncedSource) save() error {
+	return writeJSONAtomic(s.path, s.store)
+}
+
+// events returns all the events in the local copy.
+func (s *SyncedSource) events() []*calendar.Event {
+	events := make([]*calendar.Event, 0, len(s.store.Events))
+	for _, event := range s.store.Events {
+		events = append(events, event)
+	}
+	sortEventsByStart(events)
+	return events
+}
+
+// NextEventsWithin syncs, then returns the next N Zoom meetings which start within the horizon from the local copy,
+// including meetings still in progress.
+// Like NextEventsWithin, it returns a *NoMeetingsError if there are no Zoom meetings within the horizon.
+func (s *SyncedSource) NextEventsWithin(count int, horizon time.Duration) ([]*calendar.Event, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	from, to := now.Add(-5*time.Minute), now.Add(horizon)
+	if err := s.syncLocked(to); err != nil {
+		return nil, err
+	}
+
+	zoomEvents := selectEvents(s.events(), from, to, count, s.Filter)
+	if len(zoomEvents) == 0 {
+		return nil, &NoMeetingsError{Horizon: horizon}
+	}
+	return zoomEvents, nil
+}
+
+// EventsBetween syncs, then returns the Zoom meetings between from and to from the local copy.
+// Meetings which started before the synced window, a day before the last full sync, may be missing.
+func (s *SyncedSource) EventsBetween(from, to time.Time) ([]*calendar.Event, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.syncLocked(to); err != nil {
+		return nil, err
+	}
+	return selectEvents(s.events(), from, to, 0, s.Filter), nil
+}
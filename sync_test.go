@@ -0,0 +1,105 @@
+package zoom
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	calendar "google.golang.org/api/calendar/v3"
+)
+
+func syncTestEvents(t *testing.T, events ...*calendar.Event) string {
+	b, err := json.Marshal(events)
+	require.NoError(t, err)
+	return string(b)
+}
+
+func summaries(events []*calendar.Event) []string {
+	result := []string{}
+	for _, event := range events {
+		result = append(result, event.Summary)
+	}
+	return result
+}
+
+func TestSyncedSource(t *testing.T) {
+	mux := http.NewServeMux()
+	service, shutdown := newFakeGoogleCalendarService(t, mux)
+	defer shutdown()
+
+	now := time.Now()
+	standup := testEvent("standup", "1", now.Add(time.Hour))
+	retro := testEvent("retro", "1", now.Add(2*time.Hour))
+	lunch := &calendar.Event{Id: "lunch", Summary: "lunch", Start: &calendar.EventDateTime{
+		DateTime: now.Add(90 * time.Minute).Format(googleCalendarDateTimeFormat),
+	}}
+	planning := testEvent("planning", "1", now.Add(30*time.Minute))
+	movedRetro := testEvent("retro", "1", now.Add(10*time.Minute))
+
+	requests := []string{}
+	mux.HandleFunc("/calendars/primary/events", func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		requests = append(requests, query.Get("syncToken")+"/"+query.Get("pageToken"))
+
+		switch query.Get("syncToken") + "/" + query.Get("pageToken") {
+		case "/":
+			assert.NotEmpty(t, query.Get("timeMin"))
+			assert.NotEmpty(t, query.Get("timeMax"))
+			fmt.Fprintf(w, `{"nextPageToken": "p2", "items": %s}`, syncTestEvents(t, standup, lunch))
+		case "/p2":
+			fmt.Fprintf(w, `{"nextSyncToken": "t1", "items": %s}`, syncTestEvents(t, retro))
+		case "t1/":
+			assert.Empty(t, query.Get("timeMin"))
+			cancelled := &calendar.Event{Id: "standup", Status: "cancelled"}
+			fmt.Fprintf(w, `{"nextSyncToken": "t2", "items": %s}`, syncTestEvents(t, cancelled, planning, movedRetro))
+		case "t2/":
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusGone)
+			fmt.Fprint(w, `{"error": {"code": 410, "errors": [{"reason": "fullSyncRequired"}]}}`)
+		default:
+			t.Errorf("unexpected request: %s", r.URL)
+			http.NotFound(w, r)
+		}
+	})
+
+	path := filepath.Join(t.TempDir(), "sync.json")
+	source := NewSyncedSource(service, path)
+
+	events, err := source.NextEventsWithin(5, DefaultHorizon)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"standup", "retro"}, summaries(events))
+	assert.Equal(t, []string{"/", "/p2"}, requests)
+
+	// A new source reads the persisted copy and syncs incrementally.
+	source = NewSyncedSource(service, path)
+	events, err = source.EventsBetween(now, now.Add(3*time.Hour))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"retro", "planning"}, summaries(events))
+	assert.Equal(t, []string{"/", "/p2", "t1/"}, requests)
+
+	// An expired sync token triggers a full resync.
+	events, err = source.NextEventsWithin(1, DefaultHorizon)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"standup"}, summaries(events))
+	assert.Equal(t, []string{"/", "/p2", "t1/", "t2/", "/", "/p2"}, requests)
+}
+
+func TestSyncedSource_NoMeetings(t *testing.T) {
+	mux := http.NewServeMux()
+	service, shutdown := newFakeGoogleCalendarService(t, mux)
+	defer shutdown()
+
+	mux.HandleFunc("/calendars/primary/events", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `{"nextSyncToken": "t1", "items": [{"id": "lunch", "summary": "lunch", "start": {"dateTime": %q}}]}`,
+			time.Now().Add(time.Hour).Format(googleCalendarDateTimeFormat))
+	})
+
+	source := NewSyncedSource(service, filepath.Join(t.TempDir(), "sync.json"))
+	_, err := source.NextEventsWithin(1, DefaultHorizon)
+	assert.ErrorIs(t, err, ErrNoMeetings)
+}
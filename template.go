@@ -0,0 +1,112 @@
+package zoom
+
+import (
+	"io"
+	"strings"
+	"text/template"
+	"time"
+	"unicode/utf8"
+
+	humanize "github.com/dustin/go-humanize"
+	"github.com/pkg/errors"
+)
+
+// DefaultTemplate is the template used to print a meeting when none is configured.
+const DefaultTemplate = `{{.Summary}}
+{{with .RSVP}}Status: {{.}}
+{{end}}{{if .Start.IsZero}}This meeting does not have a start time...?
+{{else}}{{if .AllDay}}It's an all-day event on {{.Start.Format "Monday, January 2"}}.
+{{else if .InProgress}}It started {{.Until}} and is in progress ({{.TimeLeft}}).
+{{else}}It {{if .Started}}started{{else}}starts{{end}} {{.Until}}{{with .Length}} and lasts {{.}}{{end}}.
+{{end}}{{if not .AllDay}}When: {{.Times}}{{with .OriginalTimes}} ({{.}}){{end}}
+{{end}}{{with .Recurrence}}This is {{.}}.
+{{end}}Calendar event URL: {{.CalendarURL}}
+
+Zoom URL: {{.URL}}
+{{end}}`
+
+var colorCodes = map[string]string{
+	"black":   "30",
+	"red":     "31",
+	"green":   "32",
+	"yellow":  "33",
+	"blue":    "34",
+	"magenta": "35",
+	"cyan":    "36",
+	"white":   "37",
+	"bold":    "1",
+	"faint":   "2",
+}
+
+// fileNameReplacer replaces the characters which aren't allowed in file names on common systems.
+var fileNameReplacer = strings.NewReplacer("/", "-", "\\", "-", ":", "-", "*", "-", "?", "", "\"", "'", "<", "", ">", "", "|", "-")
+
+// TemplateFuncs are the helper functions available to meeting templates.
+var TemplateFuncs = template.FuncMap{
+	// humanize converts a time to a relative statement, e.g. "3 minutes from now".
+	"humanize": func(t time.Time) string {
+		if t.IsZero() {
+			return ""
+		}
+		return humanize.Time(t)
+	},
+
+	// localtime formats a time in the time zone the meeting is displayed in, e.g. {{.Start | localtime "15:04"}}.
+	// RenderMeeting replaces it for each meeting; outside of it, the local time zone is used.
+	"localtime": localtimeIn(time.Local),
+
+	// truncate shortens a string to at most n characters, e.g. {{.Title | truncate 20}}.
+	"truncate": func(n int, s string) string {
+		if n <= 0 || utf8.RuneCountInString(s) <= n {
+			return s
+		}
+		runes := []rune(s)
+		if n == 1 {
+			return "…"
+		}
+		return string(runes[:n-1]) + "…"
+	},
+
+	// filename replaces the characters which aren't allowed in file names, e.g. {{.Title | filename}}.
+	"filename": func(s string) string {
+		return strings.TrimSpace(fileNameReplacer.Replace(s))
+	},
+
+	// color wraps a string in ANSI color codes, e.g. {{.Title | color "green"}}.
+	"color": func(name, s string) string {
+		code, ok := colorCodes[name]
+		if !ok {
+			return s
+		}
+		return "\x1b[" + code + "m" + s + "\x1b[0m"
+	},
+}
+
+// ParseTemplate parses the text as a meeting template with the helper functions available.
+func ParseTemplate(text string) (*template.Template, error) {
+	tmpl, err := template.New("meeting").Funcs(TemplateFuncs).Parse(text)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	return tmpl, nil
+}
+
+// RenderMeeting renders the meeting through the template and writes the output to w.
+func RenderMeeting(w io.Writer, tmpl *template.Template, meeting Meeting) error {
+	tmpl, err := tmpl.Clone()
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	tmpl.Funcs(template.FuncMap{"localtime": localtimeIn(meeting.location())})
+	return errors.WithStack(tmpl.Execute(w, meeting))
+}
+
+// localtimeIn returns the localtime template function for the time zone.
+func localtimeIn(loc *time.Location) func(string, time.Time) string {
+	return func(layout string, t time.Time) string {
+		if t.IsZero() {
+			return ""
+		}
+		return t.In(loc).Format(layout)
+	}
+}
@@ -0,0 +1,112 @@
+package zoom
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	calendar "google.golang.org/api/calendar/v3"
+)
+
+func TestRenderMeeting(t *testing.T) {
+	event := &calendar.Event{
+		Summary:   "Design review",
+		HtmlLink:  "https://calendar.google.com/event?eid=1234",
+		Location:  "https://jithub.zoom.us/j/12345",
+		Organizer: &calendar.EventOrganizer{DisplayName: "Kevin Jithub"},
+		Start: &calendar.EventDateTime{
+			DateTime: time.Now().Add(12 * time.Minute).Format(googleCalendarDateTimeFormat),
+		},
+	}
+
+	testCases := []struct {
+		template string
+		expected string
+	}{
+		{"{{.Title}} {{.Until}}", "Design review 11 minutes from now"},
+		{"{{.Title | truncate 8}}", "Design …"},
+		{"{{.Title | truncate 20}}", "Design review"},
+		{"{{.Organizer | color \"green\"}}", "\x1b[32mKevin Jithub\x1b[0m"},
+		{"{{.Organizer | color \"chartreuse\"}}", "Kevin Jithub"},
+		{"{{.Start | humanize}}", "11 minutes from now"},
+		{"{{.Start | localtime \"2006-01-02\"}}", time.Now().Add(12 * time.Minute).Format("2006-01-02")},
+		{"{{.URL}}", "zoommtg://zoom.us/join?confno=12345"},
+		{
+			DefaultTemplate,
+			"Your next meeting is \"Design review\", organized by Kevin Jithub.\n" +
+				"It starts 11 minutes from now.\n" +
+				"When: " + NewMeeting(event).Times() + "\n" +
+				"Calendar event URL: https://calendar.google.com/event?eid=1234\n\n" +
+				"Zoom URL: zoommtg://zoom.us/join?confno=12345\n",
+		},
+	}
+	for _, testCase := range testCases {
+		tmpl, err := ParseTemplate(testCase.template)
+		require.NoError(t, err)
+
+		var output bytes.Buffer
+		require.NoError(t, RenderMeeting(&output, tmpl, NewMeeting(event)))
+		assert.Equal(t, testCase.expected, output.String(), "template: %q", testCase.template)
+	}
+}
+
+func TestRenderMeeting_LocaltimeInMeetingZone(t *testing.T) {
+	tokyo, err := time.LoadLocation("Asia/Tokyo")
+	require.NoError(t, err)
+	tmpl, err := ParseTemplate(`{{.Start | localtime "Jan 2 15:04 MST"}}`)
+	require.NoError(t, err)
+
+	start := time.Date(2018, time.October, 8, 23, 30, 0, 0, time.UTC)
+	var output bytes.Buffer
+	require.NoError(t, RenderMeeting(&output, tmpl, NewMeeting(testEvent("standup", "1", start)).In(tokyo)))
+	assert.Equal(t, "Oct 9 08:30 JST", output.String())
+
+	// The template itself isn't changed, so other meetings are still displayed in their own zone.
+	output.Reset()
+	require.NoError(t, RenderMeeting(&output, tmpl, NewMeeting(testEvent("standup", "1", start)).In(time.UTC)))
+	assert.Equal(t, "Oct 8 23:30 UTC", output.String())
+}
+
+func TestRenderMeeting_NoStartTime(t *testing.T) {
+	tmpl, err := ParseTemplate(DefaultTemplate)
+	require.NoError(t, err)
+
+	var output bytes.Buffer
+	require.NoError(t, RenderMeeting(&output, tmpl, NewMeeting(&calendar.Event{})))
+	assert.Equal(t, "You have a meeting coming up.\nThis meeting does not have a start time...?\n", output.String())
+}
+
+func TestRenderMeeting_InProgress(t *testing.T) {
+	tmpl, err := ParseTemplate(DefaultTemplate)
+	require.NoError(t, err)
+
+	now := time.Now().Truncate(time.Second)
+	workshop := testEvent("Workshop", "1", now.Add(-20*time.Minute))
+	workshop.End = &calendar.EventDateTime{DateTime: now.Add(35 * time.Minute).Format(googleCalendarDateTimeFormat)}
+	var output bytes.Buffer
+	require.NoError(t, RenderMeeting(&output, tmpl, NewMeeting(workshop)))
+	assert.Contains(t, output.String(), "It started 20 minutes ago and is in progress (35 min left).\n")
+
+	upcoming := testEvent("Workshop", "1", now.Add(time.Hour))
+	upcoming.End = &calendar.EventDateTime{DateTime: now.Add(150 * time.Minute).Format(googleCalendarDateTimeFormat)}
+	output.Reset()
+	require.NoError(t, RenderMeeting(&output, tmpl, NewMeeting(upcoming)))
+	assert.Contains(t, output.String(), " and lasts 1 hr 30 min.\n")
+}
+
+func TestRenderMeeting_AllDay(t *testing.T) {
+	tmpl, err := ParseTemplate(DefaultTemplate)
+	require.NoError(t, err)
+
+	event := testEvent("Offsite", "Offsite", time.Date(2018, time.October, 10, 0, 0, 0, 0, time.Local), allDay())
+	var output bytes.Buffer
+	require.NoError(t, RenderMeeting(&output, tmpl, NewMeeting(event)))
+	assert.Contains(t, output.String(), "\"Offsite\".\nIt's an all-day event on Wednesday, October 10.\n")
+}
+
+func TestParseTemplate_Invalid(t *testing.T) {
+	_, err := ParseTemplate("{{.Title")
+	assert.Error(t, err)
+}
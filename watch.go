@@ -0,0 +1,322 @@
+package zoom
+
+import (
+	"context"
+	"io/ioutil"
+	"log"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	// DefaultJoinBefore is how long before a meeting starts the Watcher opens it.
+	DefaultJoinBefore = time.Minute
+	// DefaultPollInterval is how often the Watcher checks the calendar for changes.
+	DefaultPollInterval = 5 * time.Minute
+
+	// watchHorizon is how far ahead the Watcher looks for meetings.
+	watchHorizon = 24 * time.Hour
+	// watchMaxMeetings is the most meetings the Watcher tracks at once.
+	watchMaxMeetings = 50
+	// watchJoinGrace is how long after a meeting starts the Watcher still opens it, like IsMeetingSoon.
+	watchJoinGrace = 5 * time.Minute
+	// watchMaxSleep caps how long the Watcher sleeps, so it notices wall clock jumps, e.g. after a suspend,
+	// during which monotonic timers don't advance.
+	watchMaxSleep = 30 * time.Second
+)
+
+// Clock tells the time and waits. It's an interface so the Watcher can be tested with a fake clock.
+type Clock interface {
+	Now() time.Time
+	After(d time.Duration) <-chan time.Time
+}
+
+// SystemClock is the Clock backed by the system time.
+var SystemClock Clock = systemClock{}
+
+type systemClock struct{}
+
+func (systemClock) Now() time.Time                         { return time.Now() }
+func (systemClock) After(d time.Duration) <-chan time.Time { return time.After(d) }
+
+// Opener opens a meeting's join URL.
+type Opener interface {
+	Open(url string) error
+}
+
+// OpenerFunc adapts a function to an Opener.
+type OpenerFunc func(url string) error
+
+// Open calls f(url).
+func (f OpenerFunc) Open(url string) error {
+	return f(url)
+}
+
+// Watcher runs continuously, notifying about meetings and opening each meeting's join URL shortly before it starts.
+// It polls the source for calendar changes, so moved meetings are handled at their new time
+// and cancelled meetings are ignored.
+type Watcher struct {
+	// Source provides the upcoming meetings.
+	Source EventSource
+	// Opener opens the join URLs. If it's nil, meetings aren't opened automatically.
+	Opener Opener
+	// Notifier shows notifications before meetings. If it's nil, no notifications are shown.
+	Notifier Notifier
+	// Clock defaults to SystemClock.
+	Clock Clock
+	// Logger logs what the Watcher does. It defaults to discarding the logs.
+	Logger *log.Logger
+	// JoinBefore is how long before a meeting starts to open it. It defaults to DefaultJoinBefore.
+	JoinBefore time.Duration
+	// NotifyBefore are how long before a meeting starts to show notifications. It defaults to DefaultNotifyBefore.
+	NotifyBefore []time.Duration
+	// QuietHours are when notifications aren't shown.
+	QuietHours QuietHours
+	// PollInterval is how often to check the calendar for changes. It defaults to DefaultPollInterval.
+	PollInterval time.Duration
+	// Filter decides which meetings are notified about and opened. Meetings it doesn't join automatically,
+	// like all-day events, aren't notified about or opened, but the after-meeting hooks still run for them.
+	Filter Filter
+	// Hooks run before the Watcher opens a meeting and when the meetings it watches end.
+	// A before-join hook which aborts stops the meeting being opened.
+	Hooks []MeetingHook
+
+	meetings []Meeting
+	lastPoll time.Time
+	// done records the meeting start time each action was taken for, so a moved meeting is handled again.
+	done map[watchAction]time.Time
+}
+
+// watchAction is something the Watcher does for a meeting: opening it, notifying a lead time before it starts,
+// or running the after-meeting hooks when it ends.
+type watchAction struct {
+	meetingID string
+	notify    bool
+	ended     bool
+	before    time.Duration
+}
+
+// Run watches the calendar until the context is done.
+func (w *Watcher) Run(ctx context.Context) error {
+	for {
+		wait := w.tick()
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-w.clock().After(wait):
+		}
+	}
+}
+
+// tick polls the calendar if it's due, notifies about and opens any meetings which are about to start,
+// and returns how long to sleep until the next tick.
+func (w *Watcher) tick() time.Duration {
+	now := w.clock().Now()
+
+	// Poll when due. A wall clock jump backwards, e.g. a manual clock change, also triggers a poll.
+	if w.lastPoll.IsZero() || now.Sub(w.lastPoll) >= w.pollInterval() || now.Before(w.lastPoll) {
+		w.poll(now)
+	}
+
+	if w.done == nil {
+		w.done = map[watchAction]time.Time{}
+	}
+
+	next := w.lastPoll.Add(w.pollInterval()).Sub(now)
+	for _, meeting := range w.meetings {
+		if meeting.Start.IsZero() || !w.Filter.Allows(meeting.Event) {
+			continue
+		}
+		autoJoins := meeting.URL != "" && w.Filter.AutoJoins(meeting.Event)
+
+		for _, action := range w.actions(meeting) {
+			// Meetings which aren't joined automatically are still followed by the after-meeting hooks,
+			// except for all-day events, which aren't really meetings.
+			if action.ended && meeting.AllDay || !action.ended && !autoJoins {
+				continue
+			}
+			if doneStart, ok := w.done[action]; ok && doneStart.Equal(meeting.Start) {
+				continue
+			}
+
+			dueAt, missedAt := meeting.Start.Add(-action.before), meeting.Start.Add(watchJoinGrace)
+			if action.ended {
+				dueAt = meetingEnd(meeting)
+				missedAt = dueAt.Add(watchJoinGrace)
+			}
+			switch {
+			case action.notify && !now.Before(dueAt) && w.notificationSuperseded(meeting, action.before, now):
+				// Starting the Watcher, or waking up, close to a meeting only shows the latest notification.
+				w.done[action] = meeting.Start
+			case now.After(missedAt):
+				// The clock jumped past the meeting, e.g. while suspended.
+				if !action.notify && !action.ended {
+					w.logf("Missed %q, which started at %s", meeting.Title, meeting.Start.Local().Format(time.Kitchen))
+				}
+				w.done[action] = meeting.Start
+			case !now.Before(dueAt):
+				w.do(action, meeting, now)
+				w.done[action] = meeting.Start
+			case dueAt.Sub(now) < next:
+				next = dueAt.Sub(now)
+			}
+		}
+	}
+
+	if next > watchMaxSleep {
+		next = watchMaxSleep
+	}
+	if next < 0 {
+		next = 0
+	}
+	return next
+}
+
+// actions returns what the Watcher does for the meeting: notifying at each lead time, then opening it.
+func (w *Watcher) actions(meeting Meeting) []watchAction {
+	actions := []watchAction{}
+	if w.Notifier != nil {
+		for _, before := range w.notifyBefore() {
+			actions = append(actions, watchAction{meetingID: meeting.ID, notify: true, before: before})
+		}
+	}
+	if w.Opener != nil {
+		actions = append(actions, watchAction{meetingID: meeting.ID, before: w.joinBefore()})
+	}
+	if hasMeetingHooks(w.Hooks, HookAfterMeeting) {
+		actions = append(actions, watchAction{meetingID: meeting.ID, ended: true})
+	}
+	return actions
+}
+
+// notificationSuperseded returns true if a notification closer to the meeting's start than before is already due.
+func (w *Watcher) notificationSuperseded(meeting Meeting, before time.Duration, now time.Time) bool {
+	for _, other := range w.notifyBefore() {
+		if other < before && !now.Before(meeting.Start.Add(-other)) {
+			return true
+		}
+	}
+	return false
+}
+
+// do takes the action for the meeting.
+func (w *Watcher) do(action watchAction, meeting Meeting, now time.Time) {
+	if action.notify {
+		if w.QuietHours.Contains(now) {
+			w.logf("Not notifying about %q during quiet hours", meeting.Title)
+			return
+		}
+		w.logf("Notifying about %q, which starts at %s", meeting.Title, meeting.Start.Local().Format(time.Kitchen))
+		if err := w.Notifier.Notify(NewNotification(meeting, now)); err != nil {
+			w.logf("Unable to notify about %q: %v", meeting.Title, err)
+		}
+		return
+	}
+
+	if action.ended {
+		w.logf("%q ended at %s", meeting.Title, meetingEnd(meeting).Local().Format(time.Kitchen))
+		RunMeetingHooks(context.Background(), w.Hooks, HookAfterMeeting, meeting, now, w.logger())
+		return
+	}
+
+	var aborted *HookAbortedError
+	switch err := JoinMeeting(context.Background(), w.Opener, w.Hooks, meeting, now, w.logger()); {
+	case errors.As(err, &aborted):
+		w.logf("Not opening %q: %v", meeting.Title, err)
+	case err != nil:
+		w.logf("Unable to open %q: %v", meeting.Title, err)
+	default:
+		w.logf("Opening %q, which starts at %s: %s", meeting.Title, meeting.Start.Local().Format(time.Kitchen), meeting.URL)
+	}
+}
+
+// poll refreshes the meetings from the source, logging any changes.
+// If the source fails, the previous meetings are kept.
+func (w *Watcher) poll(now time.Time) {
+	w.lastPoll = now
+
+	events, err := w.Source.NextEventsWithin(watchMaxMeetings, watchHorizon)
+	if err != nil && !errors.Is(err, ErrNoMeetings) {
+		w.logf("Unable to check calendar: %v", err)
+		return
+	}
+
+	meetings := make([]Meeting, 0, len(events))
+	for _, event := range events {
+		meetings = append(meetings, NewMeeting(event))
+	}
+
+	previous := map[string]Meeting{}
+	for _, meeting := range w.meetings {
+		previous[meeting.ID] = meeting
+	}
+	for _, meeting := range meetings {
+		old, ok := previous[meeting.ID]
+		switch {
+		case !ok:
+			w.logf("Scheduled %q at %s", meeting.Title, meeting.Start.Local().Format(time.Kitchen))
+		case !old.Start.Equal(meeting.Start):
+			w.logf("Rescheduled %q from %s to %s", meeting.Title, old.Start.Local().Format(time.Kitchen), meeting.Start.Local().Format(time.Kitchen))
+		}
+		delete(previous, meeting.ID)
+	}
+	for _, meeting := range previous {
+		if meeting.Start.After(now) {
+			w.logf("Cancelled %q at %s", meeting.Title, meeting.Start.Local().Format(time.Kitchen))
+		}
+	}
+
+	w.meetings = meetings
+
+	// Forget the actions taken for meetings which are over or were cancelled.
+	current := map[string]bool{}
+	for _, meeting := range meetings {
+		current[meeting.ID] = true
+	}
+	for action := range w.done {
+		if !current[action.meetingID] {
+			delete(w.done, action)
+		}
+	}
+}
+
+func (w *Watcher) clock() Clock {
+	if w.Clock == nil {
+		return SystemClock
+	}
+	return w.Clock
+}
+
+func (w *Watcher) joinBefore() time.Duration {
+	if w.JoinBefore <= 0 {
+		return DefaultJoinBefore
+	}
+	return w.JoinBefore
+}
+
+func (w *Watcher) notifyBefore() []time.Duration {
+	if w.NotifyBefore == nil {
+		return DefaultNotifyBefore
+	}
+	return w.NotifyBefore
+}
+
+func (w *Watcher) pollInterval() time.Duration {
+	if w.PollInterval <= 0 {
+		return DefaultPollInterval
+	}
+	return w.PollInterval
+}
+
+func (w *Watcher) logf(format string, args ...interface{}) {
+	w.logger().Printf(format, args...)
+}
+
+func (w *Watcher) logger() *log.Logger {
+	if w.Logger == nil {
+		w.Logger = log.New(ioutil.Discard, "", 0)
+	}
+	return w.Logger
+}
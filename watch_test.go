@@ -0,0 +1,221 @@
+package zoom
+
+import (
+	"bytes"
+	"context"
+	"log"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	calendar "google.golang.org/api/calendar/v3"
+)
+
+// fakeClock is a Clock whose time only changes when the test says so.
+type fakeClock struct {
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time { return c.now }
+
+func (c *fakeClock) After(d time.Duration) <-chan time.Time {
+	ch := make(chan time.Time, 1)
+	ch <- c.now.Add(d)
+	return ch
+}
+
+func (c *fakeClock) Advance(d time.Duration) { c.now = c.now.Add(d) }
+
+// fakeSource is an EventSource which returns whatever events the test sets.
+type fakeSource struct {
+	mu     sync.Mutex
+	events []*calendar.Event
+	err    error
+	polls  int
+}
+
+func (s *fakeSource) set(events ...*calendar.Event) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.events = events
+}
+
+func (s *fakeSource) NextEventsWithin(count int, horizon time.Duration) ([]*calendar.Event, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.polls++
+	return s.events, s.err
+}
+
+func (s *fakeSource) EventsBetween(from, to time.Time) ([]*calendar.Event, error) {
+	return s.NextEventsWithin(0, to.Sub(from))
+}
+
+// fakeOpener records the URLs it was asked to open.
+type fakeOpener struct {
+	opened []string
+}
+
+func (o *fakeOpener) Open(url string) error {
+	o.opened = append(o.opened, url)
+	return nil
+}
+
+func newTestWatcher(start time.Time) (*Watcher, *fakeClock, *fakeSource, *fakeOpener, *bytes.Buffer) {
+	clock := &fakeClock{now: start}
+	source := &fakeSource{}
+	opener := &fakeOpener{}
+	logs := &bytes.Buffer{}
+	watcher := &Watcher{
+		Source:       source,
+		Opener:       opener,
+		Clock:        clock,
+		Logger:       log.New(logs, "", 0),
+		JoinBefore:   2 * time.Minute,
+		PollInterval: 5 * time.Minute,
+	}
+	return watcher, clock, source, opener, logs
+}
+
+func TestWatcher_OpensMeetingBeforeStart(t *testing.T) {
+	start := time.Date(2018, time.October, 10, 9, 0, 0, 0, time.UTC)
+	watcher, clock, source, opener, logs := newTestWatcher(start)
+	source.set(testEvent("standup", "1", start.Add(10*time.Minute)))
+
+	// Sleeps are capped, so the watcher notices clock jumps.
+	assert.Equal(t, watchMaxSleep, watcher.tick())
+	assert.Empty(t, opener.opened)
+
+	clock.Advance(7*time.Minute + 50*time.Second)
+	assert.Equal(t, 10*time.Second, watcher.tick())
+	assert.Empty(t, opener.opened)
+
+	clock.Advance(10 * time.Second)
+	watcher.tick()
+	assert.Equal(t, []string{"zoommtg://zoom.us/join?confno=1"}, opener.opened)
+
+	// It only opens the meeting once.
+	clock.Advance(time.Minute)
+	watcher.tick()
+	assert.Len(t, opener.opened, 1)
+	assert.Contains(t, logs.String(), `Opening "standup"`)
+}
+
+func TestWatcher_SkipsAllDayEvents(t *testing.T) {
+	midnight := time.Date(2018, time.October, 10, 0, 0, 0, 0, time.Local)
+	focusTime := testEvent("focus", "2", midnight.Add(30*time.Minute))
+	focusTime.EventType = "focusTime"
+
+	run := func(filter Filter) []string {
+		watcher, clock, source, opener, _ := newTestWatcher(midnight.Add(-time.Hour))
+		watcher.Filter = filter
+		source.set(testEvent("offsite", "offsite", midnight, allDay()), focusTime)
+		for i := 0; i < 6; i++ {
+			watcher.tick()
+			clock.Advance(15 * time.Minute)
+		}
+		return opener.opened
+	}
+
+	assert.Empty(t, run(DefaultFilter))
+	assert.Equal(t, []string{"zoommtg://zoom.us/join?confno=offsite"}, run(Filter{Handling: map[EventKind]Handling{KindAllDay: HandleJoin}}))
+}
+
+func TestWatcher_HandlesCalendarChanges(t *testing.T) {
+	start := time.Date(2018, time.October, 10, 9, 0, 0, 0, time.UTC)
+	watcher, clock, source, opener, logs := newTestWatcher(start)
+	source.set(
+		testEvent("standup", "1", start.Add(10*time.Minute)),
+		testEvent("retro", "2", start.Add(30*time.Minute)),
+	)
+	watcher.tick()
+
+	// Between polls, the standup moves later and the retro is cancelled.
+	source.set(testEvent("standup", "1", start.Add(40*time.Minute)))
+	clock.Advance(5 * time.Minute)
+	watcher.tick()
+	assert.Contains(t, logs.String(), `Rescheduled "standup"`)
+	assert.Contains(t, logs.String(), `Cancelled "retro"`)
+
+	for i := 0; i < 20; i++ {
+		clock.Advance(time.Minute)
+		watcher.tick()
+	}
+	assert.Empty(t, opener.opened)
+
+	clock.Advance(14 * time.Minute)
+	watcher.tick()
+	assert.Equal(t, []string{"zoommtg://zoom.us/join?confno=1"}, opener.opened)
+}
+
+func TestWatcher_ForgetsPastMeetings(t *testing.T) {
+	start := time.Date(2018, time.October, 10, 9, 0, 0, 0, time.UTC)
+	watcher, clock, source, opener, _ := newTestWatcher(start)
+	source.set(testEvent("standup", "1", start.Add(10*time.Minute)))
+	watcher.tick()
+	clock.Advance(10 * time.Minute)
+	watcher.tick()
+	require.Len(t, opener.opened, 1)
+	assert.NotEmpty(t, watcher.done)
+
+	// Once the standup is no longer upcoming, nothing is remembered about it.
+	source.set(testEvent("retro", "2", start.Add(time.Hour)))
+	clock.Advance(watcher.pollInterval())
+	watcher.tick()
+	for action := range watcher.done {
+		assert.NotEqual(t, "standup", action.meetingID)
+	}
+}
+
+func TestWatcher_ClockJumps(t *testing.T) {
+	start := time.Date(2018, time.October, 10, 9, 0, 0, 0, time.UTC)
+	watcher, clock, source, opener, logs := newTestWatcher(start)
+	source.set(
+		testEvent("standup", "1", start.Add(10*time.Minute)),
+		testEvent("retro", "2", start.Add(time.Hour)),
+	)
+	watcher.tick()
+
+	// The laptop is suspended through the standup and resumed just before the retro.
+	clock.Advance(59 * time.Minute)
+	watcher.tick()
+	assert.Equal(t, []string{"zoommtg://zoom.us/join?confno=2"}, opener.opened)
+	assert.Contains(t, logs.String(), `Missed "standup"`)
+	assert.Equal(t, 2, source.polls)
+}
+
+func TestWatcher_KeepsMeetingsWhenPollFails(t *testing.T) {
+	start := time.Date(2018, time.October, 10, 9, 0, 0, 0, time.UTC)
+	watcher, clock, source, opener, logs := newTestWatcher(start)
+	source.set(testEvent("standup", "1", start.Add(10*time.Minute)))
+	watcher.tick()
+
+	source.err = errors.New("network is down")
+	clock.Advance(9 * time.Minute)
+	watcher.tick()
+	assert.Contains(t, logs.String(), "Unable to check calendar: network is down")
+	assert.Len(t, opener.opened, 1)
+}
+
+func TestWatcher_Run(t *testing.T) {
+	start := time.Now()
+	source := &fakeSource{}
+	source.set(testEvent("standup", "1", start.Add(time.Minute)))
+	opener := &fakeOpener{}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	watcher := &Watcher{
+		Source: source,
+		Opener: OpenerFunc(func(url string) error {
+			cancel()
+			return opener.Open(url)
+		}),
+		JoinBefore: 2 * time.Minute,
+	}
+	err := watcher.Run(ctx)
+	require.Equal(t, context.Canceled, err)
+	assert.Equal(t, []string{"zoommtg://zoom.us/join?confno=1"}, opener.opened)
+}
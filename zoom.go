@@ -11,83 +11,202 @@ import (
 	humanize "github.com/dustin/go-humanize"
 	"github.com/pkg/errors"
 	calendar "google.golang.org/api/calendar/v3"
+	"google.golang.org/api/googleapi"
 )
 
 const googleCalendarDateTimeFormat = time.RFC3339
 
-// NextEvents returns the next N calendar events in your primary calendar.
+// googleCalendarDateFormat is the format of the dates of all-day events.
+const googleCalendarDateFormat = "2006-01-02"
+
+// eventsPageSize is the number of events requested per page when listing a time range.
+const eventsPageSize = 250
+
+// eventFields are the event fields the package uses. Only these are requested,
+// which keeps responses small, since descriptions, attendees and attachments add up quickly.
+const eventFields = "id,iCalUID,recurringEventId,status,summary,htmlLink,location,description," +
+	"eventType,colorId,organizer(displayName,email,self),creator(displayName,email),start,end," +
+	"attendees(email,displayName,self,organizer,resource,responseStatus)," +
+	"conferenceData/entryPoints(entryPointType,uri)"
+
+// eventsListFields is the partial response requested when listing events.
+const eventsListFields googleapi.Field = "nextPageToken,nextSyncToken,items(" + eventFields + ")"
+
+// DefaultHorizon is how far ahead NextEvents looks for Zoom meetings.
+const DefaultHorizon = 7 * 24 * time.Hour
+
+// NextEvents returns the next N calendar events in your primary calendar within the DefaultHorizon.
 // It only returns events which contain Zoom video chats.
 func NextEvents(service *calendar.Service, count int) ([]*calendar.Event, error) {
-	t := time.Now().Add(-5 * time.Minute).Format(time.RFC3339)
+	return NextEventsWithin(service, count, DefaultHorizon)
+}
+
+// NextEventsWithin returns the next N calendar events in your primary calendar which start within the horizon,
+// including meetings which are still in progress or started less than 5 minutes ago.
+// It only returns events which contain Zoom video chats, following pagination until it has found N of them.
+// If there are no Zoom meetings within the horizon, it returns a *NoMeetingsError.
+// Declined and cancelled meetings are left out, like DefaultFilter.
+func NextEventsWithin(service *calendar.Service, count int, horizon time.Duration) ([]*calendar.Event, error) {
+	return nextEventsWithin(service, count, horizon, DefaultFilter)
+}
 
-	events, err := service.Events.
+func nextEventsWithin(service *calendar.Service, count int, horizon time.Duration, filter Filter) ([]*calendar.Event, error) {
+	now := time.Now()
+
+	call := service.Events.
 		List("primary").
-		ShowDeleted(false).
+		ShowDeleted(filter.IncludeCancelled).
 		SingleEvents(true).
-		TimeMin(t).
+		TimeMin(now.Add(-5 * time.Minute).Format(time.RFC3339)).
+		TimeMax(now.Add(horizon).Format(time.RFC3339)).
 		MaxResults(int64(count * 10)).
 		OrderBy("startTime").
-		Do()
+		Fields(eventsListFields)
+
+	zoomEvents := []*calendar.Event{}
+	var lastStart time.Time
+	err := eachEvent(call, func(event *calendar.Event) bool {
+		start, _ := MeetingStartTime(event)
+		// Once there are enough meetings, carry on until the start time changes, so a meeting you've accepted
+		// is ranked above a tentative one starting at the same time before the list is cut.
+		if len(zoomEvents) >= count && !start.Equal(lastStart) {
+			return false
+		}
+		if _, ok := MeetingURLFromEvent(event); ok && filter.Allows(event) {
+			zoomEvents = append(zoomEvents, event)
+			lastStart = start
+		}
+		return true
+	})
 	if err != nil {
-		return nil, errors.WithStack(err)
+		return nil, err
+	}
+	sortEventsByStart(zoomEvents)
+	if len(zoomEvents) > count {
+		zoomEvents = zoomEvents[:count]
 	}
 
-	if len(events.Items) == 0 {
-		return nil, nil
+	if len(zoomEvents) == 0 {
+		return nil, &NoMeetingsError{Horizon: horizon}
 	}
 
-	zoomEvents := []*calendar.Event{}
-	for _, event := range events.Items {
-		if _, ok := MeetingURLFromEvent(event); !ok {
-			continue
-		}
+	return zoomEvents, nil
+}
 
-		zoomEvents = append(zoomEvents, event)
+// EventsBetween returns the calendar events in your primary calendar which start before to and end after from.
+// It follows pagination and only returns events which contain Zoom video chats, leaving out declined and cancelled
+// meetings like DefaultFilter.
+func EventsBetween(service *calendar.Service, from, to time.Time) ([]*calendar.Event, error) {
+	return SearchEventsBetween(service, "", from, to)
+}
 
-		if len(zoomEvents) == count {
-			break
-		}
+// SearchEventsBetween returns the calendar events between from and to which match the query,
+// using the Calendar API's free text search of titles, descriptions, locations, organizers and attendees.
+// Like EventsBetween, it only returns events which contain Zoom video chats. An empty query matches every event.
+func SearchEventsBetween(service *calendar.Service, query string, from, to time.Time) ([]*calendar.Event, error) {
+	return searchEventsBetween(service, query, from, to, DefaultFilter)
+}
+
+func searchEventsBetween(service *calendar.Service, query string, from, to time.Time, filter Filter) ([]*calendar.Event, error) {
+	call := service.Events.
+		List("primary").
+		ShowDeleted(filter.IncludeCancelled).
+		SingleEvents(true).
+		TimeMin(from.Format(googleCalendarDateTimeFormat)).
+		TimeMax(to.Format(googleCalendarDateTimeFormat)).
+		MaxResults(eventsPageSize).
+		OrderBy("startTime").
+		Fields(eventsListFields)
+	if query != "" {
+		call = call.Q(query)
 	}
 
-	if len(zoomEvents) == 0 {
-		return nil, errors.Errorf("no zoom events upcoming")
+	zoomEvents := []*calendar.Event{}
+	err := eachEvent(call, func(event *calendar.Event) bool {
+		if _, ok := MeetingURLFromEvent(event); ok && filter.Allows(event) {
+			zoomEvents = append(zoomEvents, event)
+		}
+		return true
+	})
+	if err != nil {
+		return nil, err
 	}
+	sortEventsByStart(zoomEvents)
 
 	return zoomEvents, nil
 }
 
-// NextEvent returns the next calendar event in your primary calendar.
-// It will list at most 5 events, and select the first one with a Zoom URL if one exists.
+// eachEvent calls fn for each event returned by the call, fetching subsequent pages as needed.
+// It stops early if fn returns false.
+func eachEvent(call *calendar.EventsListCall, fn func(*calendar.Event) bool) error {
+	pageToken := ""
+	for {
+		events, err := call.PageToken(pageToken).Do()
+		if err != nil {
+			return classifyError(err)
+		}
+
+		for _, event := range events.Items {
+			if !fn(event) {
+				return nil
+			}
+		}
+
+		if events.NextPageToken == "" {
+			return nil
+		}
+		pageToken = events.NextPageToken
+	}
+}
+
+// NextEvent returns the next calendar event with a Zoom URL in your primary calendar within the DefaultHorizon,
+// paging through the calendar like NextEvents. If there isn't one, it returns a *NoMeetingsError.
 func NextEvent(service *calendar.Service) (*calendar.Event, error) {
 	events, err := NextEvents(service, 1)
 	if err != nil {
 		return nil, err
 	}
-	if len(events) == 0 {
-		return nil, nil
-	}
 	return events[0], nil
 }
 
 // MeetingURLFromEvent returns a URL if the event is a Zoom meeting.
 func MeetingURLFromEvent(event *calendar.Event) (*url.URL, bool) {
-	input := event.Location + " " + event.Description
-	if videoEntryPointURL, ok := conferenceVideoEntryPointURL(event); ok {
-		input = videoEntryPointURL + " " + input
+	data, ok := callFromEvent(event)
+	if !ok {
+		return nil, ok
 	}
 
-	data, ok := extractZoomCallData(input)
+	parsedURL, err := url.Parse(data.GetAppURL())
+	if err != nil {
+		return nil, false
+	}
+	return parsedURL, true
+}
+
+// MeetingWebURLFromEvent returns the Zoom URL as written in the event, suitable for sharing.
+func MeetingWebURLFromEvent(event *calendar.Event) (*url.URL, bool) {
+	data, ok := callFromEvent(event)
 	if !ok {
 		return nil, ok
 	}
 
-	parsedURL, err := url.Parse(data.GetAppURL())
+	parsedURL, err := url.Parse(data.originalURL)
 	if err != nil {
 		return nil, false
 	}
 	return parsedURL, true
 }
 
+// callFromEvent extracts the Zoom call from the event's conference data, location or description.
+func callFromEvent(event *calendar.Event) (call, bool) {
+	input := event.Location + " " + event.Description
+	if videoEntryPointURL, ok := conferenceVideoEntryPointURL(event); ok {
+		input = videoEntryPointURL + " " + input
+	}
+
+	return extractZoomCallData(input)
+}
+
 // conferenceVideoEntryPointURL returns the URL for the video entrypoint if one exists.
 func conferenceVideoEntryPointURL(event *calendar.Event) (string, bool) {
 	if event.ConferenceData == nil {
@@ -103,14 +222,25 @@ func conferenceVideoEntryPointURL(event *calendar.Event) (string, bool) {
 	return "", false
 }
 
-// IsMeetingSoon returns true if the meeting is less than 5 minutes from now.
+// IsMeetingSoon returns true if the meeting is less than 5 minutes from now, or started earlier and is still
+// in progress.
 func IsMeetingSoon(event *calendar.Event) bool {
+	return isMeetingSoonAt(event, time.Now())
+}
+
+// isMeetingSoonAt returns true if the meeting is less than 5 minutes from now, or started earlier and is still
+// in progress at now.
+func isMeetingSoonAt(event *calendar.Event, now time.Time) bool {
 	startTime, err := MeetingStartTime(event)
 	if err != nil {
 		return false
 	}
-	minutesUntilStart := time.Until(startTime).Minutes()
-	return -5 < minutesUntilStart && minutesUntilStart < 5
+	minutesUntilStart := startTime.Sub(now).Minutes()
+	if -5 < minutesUntilStart && minutesUntilStart < 5 {
+		return true
+	}
+	endTime, err := MeetingEndTime(event)
+	return err == nil && startTime.Before(now) && now.Before(endTime)
 }
 
 // HumanizedStartTime converts the event's start time to a human-friendly statement.
@@ -123,11 +253,39 @@ func HumanizedStartTime(event *calendar.Event) string {
 }
 
 // MeetingStartTime returns the calendar event's start time.
+// All-day events start at midnight local time on their first day.
 func MeetingStartTime(event *calendar.Event) (time.Time, error) {
-	if event == nil || event.Start == nil || event.Start.DateTime == "" {
+	if event == nil {
 		return time.Time{}, errors.New("event does not have a start datetime")
 	}
-	return time.Parse(googleCalendarDateTimeFormat, event.Start.DateTime)
+	return parseEventDateTime(event.Start, "a start")
+}
+
+// MeetingEndTime returns the calendar event's end time.
+// All-day events end at midnight local time after their last day.
+func MeetingEndTime(event *calendar.Event) (time.Time, error) {
+	if event == nil {
+		return time.Time{}, errors.New("event does not have an end datetime")
+	}
+	return parseEventDateTime(event.End, "an end")
+}
+
+// parseEventDateTime parses an event's start or end, which has either a date and time or, for all-day events,
+// only a date.
+func parseEventDateTime(dateTime *calendar.EventDateTime, which string) (time.Time, error) {
+	switch {
+	case dateTime != nil && dateTime.DateTime != "":
+		return time.Parse(googleCalendarDateTimeFormat, dateTime.DateTime)
+	case dateTime != nil && dateTime.Date != "":
+		return time.ParseInLocation(googleCalendarDateFormat, dateTime.Date, time.Local)
+	default:
+		return time.Time{}, errors.Errorf("event does not have %s datetime", which)
+	}
+}
+
+// IsAllDay returns true if the event lasts whole days, rather than starting at a particular time.
+func IsAllDay(event *calendar.Event) bool {
+	return event != nil && event.Start != nil && event.Start.DateTime == "" && event.Start.Date != ""
 }
 
 // MeetingSummary generates a one-line summary of the meeting as a string.
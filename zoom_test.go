@@ -1,6 +1,7 @@
 package zoom
 
 import (
+	"errors"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
@@ -120,10 +121,11 @@ func TestNextEvents(t *testing.T) {
 			assert.Equal(t, query.Get("orderBy"), "startTime")
 			assert.Equal(t, query.Get("showDeleted"), "false")
 			assert.Equal(t, query.Get("singleEvents"), "true")
-			assert.Equal(t, query.Get("timeMin"), time.Now().Add(-5*time.Minute).Format(time.RFC3339))
+			assertTimeParam(t, time.Now().Add(-5*time.Minute), query.Get("timeMin"))
 			fmt.Fprint(w, testEventResponse)
 		} else {
-			t.Fatalf("unexpected request: %s %s", r.Method, r.URL)
+			t.Errorf("unexpected request: %s %s", r.Method, r.URL)
+			http.NotFound(w, r)
 		}
 	})
 
@@ -154,10 +156,11 @@ func TestNextEvent(t *testing.T) {
 			assert.Equal(t, query.Get("orderBy"), "startTime")
 			assert.Equal(t, query.Get("showDeleted"), "false")
 			assert.Equal(t, query.Get("singleEvents"), "true")
-			assert.Equal(t, query.Get("timeMin"), time.Now().Add(-5*time.Minute).Format(time.RFC3339))
+			assertTimeParam(t, time.Now().Add(-5*time.Minute), query.Get("timeMin"))
 			fmt.Fprint(w, testEventResponse)
 		} else {
-			t.Fatalf("unexpected request: %s %s", r.Method, r.URL)
+			t.Errorf("unexpected request: %s %s", r.Method, r.URL)
+			http.NotFound(w, r)
 		}
 	})
 
@@ -201,16 +204,167 @@ func TestNextEvent_NoUpcomingEvents(t *testing.T) {
 		if r.URL.Path == "/calendars/primary/events" {
 			fmt.Fprintf(w, `{"items":[]}`)
 		} else {
-			t.Fatalf("unexpected request: %s %s", r.Method, r.URL)
+			t.Errorf("unexpected request: %s %s", r.Method, r.URL)
+			http.NotFound(w, r)
 		}
 	})
 
 	event, err := NextEvent(service)
-	require.NoError(t, err)
+	assert.True(t, errors.Is(err, ErrNoMeetings))
 	assert.Equal(t, 1, actualRequests)
 	assert.Nil(t, event)
 }
 
+func TestNextEventsWithin_Pagination(t *testing.T) {
+	mux := http.NewServeMux()
+
+	service, shutdown := newFakeGoogleCalendarService(t, mux)
+	defer shutdown()
+
+	now := time.Now()
+	actualRequests := 0
+	mux.HandleFunc("/calendars/primary/events", func(w http.ResponseWriter, r *http.Request) {
+		actualRequests++
+
+		query := r.URL.Query()
+		assertTimeParam(t, now.Add(48*time.Hour), query.Get("timeMax"))
+
+		switch query.Get("pageToken") {
+		case "":
+			fmt.Fprint(w, `{"nextPageToken": "page2", "items": [
+				{"summary": "Focus time", "location": "Desk"},
+				{"summary": "Lunch", "location": "Cafeteria"}
+			]}`)
+		case "page2":
+			fmt.Fprint(w, `{"nextPageToken": "page3", "items": [
+				{"summary": "Out of office"},
+				{"summary": "Standup", "location": "https://jithub.zoom.us/j/1", "start": {"dateTime": "2018-10-10T09:00:00Z"}}
+			]}`)
+		case "page3":
+			fmt.Fprint(w, `{"nextPageToken": "page4", "items": [
+				{"summary": "Retro", "location": "https://jithub.zoom.us/j/2", "start": {"dateTime": "2018-10-10T10:00:00Z"}}
+			]}`)
+		default:
+			t.Errorf("unexpected page token: %s", query.Get("pageToken"))
+			http.Error(w, "unexpected page token", http.StatusBadRequest)
+		}
+	})
+
+	events, err := NextEventsWithin(service, 1, 48*time.Hour)
+	require.NoError(t, err)
+	assert.Equal(t, 3, actualRequests, "the next page is fetched to check nothing else starts at the same time")
+	require.Len(t, events, 1)
+	assert.Equal(t, "Standup", events[0].Summary)
+}
+
+func TestNextEventsWithin_RanksResponsesAtTheSameStart(t *testing.T) {
+	mux := http.NewServeMux()
+
+	service, shutdown := newFakeGoogleCalendarService(t, mux)
+	defer shutdown()
+
+	mux.HandleFunc("/calendars/primary/events", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"items": [
+			{"summary": "Maybe", "location": "https://jithub.zoom.us/j/1", "start": {"dateTime": "2018-10-10T09:00:00Z"},
+				"attendees": [{"self": true, "responseStatus": "tentative"}]},
+			{"summary": "Definitely", "location": "https://jithub.zoom.us/j/2", "start": {"dateTime": "2018-10-10T09:00:00Z"},
+				"attendees": [{"self": true, "responseStatus": "accepted"}]},
+			{"summary": "Later", "location": "https://jithub.zoom.us/j/3", "start": {"dateTime": "2018-10-10T10:00:00Z"},
+				"attendees": [{"self": true, "responseStatus": "accepted"}]}
+		]}`)
+	})
+
+	events, err := NextEventsWithin(service, 1, DefaultHorizon)
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+	assert.Equal(t, "Definitely", events[0].Summary)
+}
+
+func TestNextEventsWithin_NoMeetingsWithinHorizon(t *testing.T) {
+	mux := http.NewServeMux()
+
+	service, shutdown := newFakeGoogleCalendarService(t, mux)
+	defer shutdown()
+
+	mux.HandleFunc("/calendars/primary/events", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"items": [{"summary": "Lunch", "location": "Cafeteria"}]}`)
+	})
+
+	events, err := NextEventsWithin(service, 1, 72*time.Hour)
+	assert.Nil(t, events)
+	assert.True(t, errors.Is(err, ErrNoMeetings))
+	assert.EqualError(t, err, "no zoom events upcoming in the next 3 days")
+
+	var noMeetingsErr *NoMeetingsError
+	require.True(t, errors.As(err, &noMeetingsErr))
+	assert.Equal(t, 72*time.Hour, noMeetingsErr.Horizon)
+}
+
+func TestNextEventsWithin_Error(t *testing.T) {
+	mux := http.NewServeMux()
+
+	service, shutdown := newFakeGoogleCalendarService(t, mux)
+	defer shutdown()
+
+	mux.HandleFunc("/calendars/primary/events", func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, `{"error": {"code": 500, "message": "oops"}}`, http.StatusInternalServerError)
+	})
+
+	_, err := NextEventsWithin(service, 1, DefaultHorizon)
+	require.Error(t, err)
+	assert.False(t, errors.Is(err, ErrNoMeetings))
+}
+
+func TestEventsBetween(t *testing.T) {
+	mux := http.NewServeMux()
+
+	service, shutdown := newFakeGoogleCalendarService(t, mux)
+	defer shutdown()
+
+	from := time.Date(2018, time.October, 10, 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(0, 0, 1)
+
+	actualRequests := 0
+	mux.HandleFunc("/calendars/primary/events", func(w http.ResponseWriter, r *http.Request) {
+		actualRequests++
+
+		query := r.URL.Query()
+		assert.Equal(t, "2018-10-10T00:00:00Z", query.Get("timeMin"))
+		assert.Equal(t, "2018-10-11T00:00:00Z", query.Get("timeMax"))
+		assert.Equal(t, "250", query.Get("maxResults"))
+
+		switch query.Get("pageToken") {
+		case "":
+			fmt.Fprint(w, `{"nextPageToken": "page2", "items": [
+				{"summary": "Lunch", "location": "Cafeteria"},
+				{"summary": "Standup", "location": "https://jithub.zoom.us/j/1"}
+			]}`)
+		case "page2":
+			fmt.Fprint(w, `{"items": [{"summary": "Retro", "description": "https://jithub.zoom.us/j/2"}]}`)
+		default:
+			t.Errorf("unexpected page token: %s", query.Get("pageToken"))
+			http.Error(w, "unexpected page token", http.StatusBadRequest)
+		}
+	})
+
+	events, err := EventsBetween(service, from, to)
+	require.NoError(t, err)
+	assert.Equal(t, 2, actualRequests)
+	require.Len(t, events, 2)
+	assert.Equal(t, "Standup", events[0].Summary)
+	assert.Equal(t, "Retro", events[1].Summary)
+}
+
+// assertTimeParam asserts that the RFC 3339 time in a query parameter is the expected time, give or take
+// the time it took the request to arrive.
+func assertTimeParam(t *testing.T, expected time.Time, param string) {
+	t.Helper()
+	actual, err := time.Parse(time.RFC3339, param)
+	if assert.NoError(t, err) {
+		assert.WithinDuration(t, expected, actual, 5*time.Second)
+	}
+}
+
 func newFakeGoogleCalendarService(t *testing.T, mux http.Handler) (*calendar.Service, func()) {
 	service, err := calendar.New(&http.Client{})
 	if err != nil {
@@ -268,12 +422,60 @@ func TestIsMeetingSoon(t *testing.T) {
 		{&calendar.Event{Start: &calendar.EventDateTime{
 			DateTime: time.Now().Add(12 * time.Minute).Format(googleCalendarDateTimeFormat),
 		}}, false},
+		{&calendar.Event{
+			Start: &calendar.EventDateTime{DateTime: time.Now().Add(-20 * time.Minute).Format(googleCalendarDateTimeFormat)},
+			End:   &calendar.EventDateTime{DateTime: time.Now().Add(100 * time.Minute).Format(googleCalendarDateTimeFormat)},
+		}, true},
+		{&calendar.Event{
+			Start: &calendar.EventDateTime{DateTime: time.Now().Add(-time.Hour).Format(googleCalendarDateTimeFormat)},
+			End:   &calendar.EventDateTime{DateTime: time.Now().Add(-10 * time.Minute).Format(googleCalendarDateTimeFormat)},
+		}, false},
 	}
 	for _, testCase := range testCases {
 		assert.Equal(t, testCase.expected, IsMeetingSoon(testCase.input))
 	}
 }
 
+func TestMeeting_Duration(t *testing.T) {
+	start := time.Date(2018, time.October, 10, 9, 0, 0, 0, time.UTC)
+	testCases := []struct {
+		end      time.Time
+		duration time.Duration
+		length   string
+	}{
+		{time.Time{}, 0, ""},
+		{start.Add(-time.Minute), 0, ""},
+		{start.Add(25 * time.Minute), 25 * time.Minute, "25 min"},
+		{start.Add(time.Hour), time.Hour, "1 hr"},
+		{start.Add(90*time.Minute + 10*time.Second), 90*time.Minute + 10*time.Second, "1 hr 31 min"},
+		{start.AddDate(0, 0, 2), 48 * time.Hour, "2 days"},
+	}
+	for _, testCase := range testCases {
+		meeting := Meeting{Start: start, End: testCase.end}
+		assert.Equal(t, testCase.duration, meeting.Duration())
+		assert.Equal(t, testCase.length, meeting.Length())
+	}
+}
+
+func TestMeetingStartTime_AllDay(t *testing.T) {
+	event := &calendar.Event{
+		Start: &calendar.EventDateTime{Date: "2018-10-10"},
+		End:   &calendar.EventDateTime{Date: "2018-10-11"},
+	}
+	assert.True(t, IsAllDay(event))
+
+	start, err := MeetingStartTime(event)
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2018, time.October, 10, 0, 0, 0, 0, time.Local), start)
+
+	end, err := MeetingEndTime(event)
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2018, time.October, 11, 0, 0, 0, 0, time.Local), end)
+
+	_, err = MeetingStartTime(&calendar.Event{Start: &calendar.EventDateTime{Date: "October 10"}})
+	assert.Error(t, err)
+}
+
 func TestHumanizedStartTime(t *testing.T) {
 	testCases := []struct {
 		input    *calendar.Event